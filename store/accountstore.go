@@ -0,0 +1,194 @@
+package store
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/account"
+)
+
+// accountStore persists accounts under accountPrefix, keyed by address.
+// total is kept in memory so TotalAccounts doesn't need a full scan.
+//
+// pending overlays accounts that have been staged into a batch the commit
+// pipeline hasn't durably written yet, so hasAccount/account see a block's
+// own writes (and a later block's, if both are still in flight) instead of
+// stale backend data.
+type accountStore struct {
+	backend KVBackend
+	total   int32
+
+	pendingMu sync.RWMutex
+	pending   map[string]pendingEntry
+	staged    []pendingKey
+	nextSeq   uint64
+}
+
+// pendingEntry is one account's encoded bytes staged into a batch, tagged
+// with the sequence number of the write that produced it.
+type pendingEntry struct {
+	data []byte
+	seq  uint64
+}
+
+func newAccountStore(backend KVBackend) *accountStore {
+	s := &accountStore{backend: backend, pending: make(map[string]pendingEntry)}
+
+	it := backend.NewIterator(accountPrefix)
+	defer it.Release()
+	for it.Next() {
+		s.total++
+	}
+
+	return s
+}
+
+func accountKey(addr crypto.Address) []byte {
+	return append(append([]byte{}, accountPrefix...), addr.Bytes()...)
+}
+
+func (s *accountStore) hasAccount(addr crypto.Address) bool {
+	key := accountKey(addr)
+
+	s.pendingMu.RLock()
+	_, pending := s.pending[string(key)]
+	s.pendingMu.RUnlock()
+	if pending {
+		return true
+	}
+
+	ok, _ := s.backend.Has(key)
+
+	return ok
+}
+
+func (s *accountStore) account(addr crypto.Address) (*account.Account, error) {
+	key := accountKey(addr)
+
+	s.pendingMu.RLock()
+	entry, pending := s.pending[string(key)]
+	s.pendingMu.RUnlock()
+
+	data := entry.data
+	if !pending {
+		var err error
+		data, err = tryGet(s.backend, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	acc := new(account.Account)
+	if err := acc.Decode(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return acc, nil
+}
+
+// iterateAccounts walks every account, merging in the pending overlay so a
+// block's own writes (or a later block's, if both are still in flight) show
+// up even before their batch is durable.
+func (s *accountStore) iterateAccounts(consumer func(crypto.Address, *account.Account) (stop bool)) {
+	s.pendingMu.RLock()
+	pending := make(map[string][]byte, len(s.pending))
+	for key, entry := range s.pending {
+		pending[key] = entry.data
+	}
+	s.pendingMu.RUnlock()
+
+	seen := make(map[string]bool, len(pending))
+
+	it := s.backend.NewIterator(accountPrefix)
+	defer it.Release()
+
+	for it.Next() {
+		key := string(it.Key())
+		data := it.Value()
+		if overlay, ok := pending[key]; ok {
+			data = overlay
+		}
+		seen[key] = true
+
+		if decodeAccount(consumer, key, data) {
+			return
+		}
+	}
+
+	for key, data := range pending {
+		if seen[key] {
+			continue
+		}
+
+		if decodeAccount(consumer, key, data) {
+			return
+		}
+	}
+}
+
+// decodeAccount decodes the account stored under key and hands it to
+// consumer, returning whether consumer asked to stop.
+func decodeAccount(consumer func(crypto.Address, *account.Account) (stop bool), key string, data []byte) bool {
+	addr, err := crypto.AddressFromBytes([]byte(key)[len(accountPrefix):])
+	if err != nil {
+		return false
+	}
+
+	acc := new(account.Account)
+	if err := acc.Decode(bytes.NewReader(data)); err != nil {
+		return false
+	}
+
+	return consumer(addr, acc)
+}
+
+func (s *accountStore) updateAccount(batch KVBatch, addr crypto.Address, acc *account.Account) {
+	if !s.hasAccount(addr) {
+		s.total++
+	}
+
+	w := new(bytes.Buffer)
+	if err := acc.Encode(w); err != nil {
+		panic(err)
+	}
+
+	key := accountKey(addr)
+	batch.Put(key, w.Bytes())
+
+	s.pendingMu.Lock()
+	s.nextSeq++
+	seq := s.nextSeq
+	s.pending[string(key)] = pendingEntry{data: w.Bytes(), seq: seq}
+	s.staged = append(s.staged, pendingKey{key: string(key), seq: seq})
+	s.pendingMu.Unlock()
+}
+
+// takeStaged returns and clears the account keys written since the last
+// call, so WriteBatch can hand them to the pipeline: once the batch they
+// ended up in is durable (or abandoned after a commit failure), the
+// pipeline calls evictPending with this same list.
+func (s *accountStore) takeStaged() []pendingKey {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	staged := s.staged
+	s.staged = nil
+
+	return staged
+}
+
+// evictPending drops keys from the overlay once the batch that staged them
+// has been resolved, durably written or not. A key is only dropped if its
+// entry is still the one this exact write staged; a newer write to the same
+// key (from a later, still in-flight batch) is left alone.
+func (s *accountStore) evictPending(keys []pendingKey) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	for _, pk := range keys {
+		if entry, ok := s.pending[pk.key]; ok && entry.seq == pk.seq {
+			delete(s.pending, pk.key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"bytes"
+
+	"github.com/pactus-project/pactus/crypto/hash"
+	"github.com/pactus-project/pactus/types/block"
+	"github.com/pactus-project/pactus/util"
+)
+
+// blockStore persists committed blocks under blockPrefix, keyed by height,
+// and maintains a blockHeightPrefix index from block hash back to height.
+type blockStore struct {
+	backend KVBackend
+}
+
+func newBlockStore(backend KVBackend) *blockStore {
+	return &blockStore{backend: backend}
+}
+
+func blockKey(height uint32) []byte {
+	return append(append([]byte{}, blockPrefix...), util.Uint32ToSlice(height)...)
+}
+
+func blockHeightKey(h hash.Hash) []byte {
+	return append(append([]byte{}, blockHeightPrefix...), h.Bytes()...)
+}
+
+// saveBlock stores blk under height as [block hash][encoded block][encoded
+// transactions, back to back] and indexes its hash for blockHeight. The
+// transactions are appended again, separately from the block's own
+// encoding, so txStore can record a direct offset/length into this same
+// value instead of having to re-decode the whole block on every lookup.
+// It returns the position of each of blk's transactions, in order, for
+// txStore to save alongside it.
+func (s *blockStore) saveBlock(batch KVBatch, height uint32, blk *block.Block) []txPos {
+	blockHash := blk.Hash()
+
+	blockBuf := new(bytes.Buffer)
+	if err := blk.Encode(blockBuf); err != nil {
+		panic(err)
+	}
+
+	txs := blk.Transactions()
+	encodedTxs := make([][]byte, len(txs))
+	for i, trx := range txs {
+		txBuf := new(bytes.Buffer)
+		if err := trx.Encode(txBuf); err != nil {
+			panic(err)
+		}
+		encodedTxs[i] = txBuf.Bytes()
+	}
+
+	data := make([]byte, 0, hash.HashSize+blockBuf.Len())
+	data = append(data, blockHash.Bytes()...)
+	data = append(data, blockBuf.Bytes()...)
+
+	positions := make([]txPos, len(txs))
+	for i, txBytes := range encodedTxs {
+		positions[i] = txPos{height: height, offset: uint32(len(data)), length: uint32(len(txBytes))}
+		data = append(data, txBytes...)
+	}
+
+	batch.Put(blockKey(height), data)
+	batch.Put(blockHeightKey(blockHash), util.Uint32ToSlice(height))
+
+	return positions
+}
+
+func (s *blockStore) block(height uint32) ([]byte, error) {
+	return tryGet(s.backend, blockKey(height))
+}
+
+func (s *blockStore) blockHeight(h hash.Hash) uint32 {
+	data, err := tryGet(s.backend, blockHeightKey(h))
+	if err != nil {
+		return 0
+	}
+
+	return util.SliceToUint32(data)
+}
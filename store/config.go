@@ -0,0 +1,28 @@
+package store
+
+// Config configures the on-disk (or in-memory) store.
+type Config struct {
+	Path    string  `toml:"path"`
+	Backend Backend `toml:"backend"`
+
+	// PipelineDepth bounds how many built batches WriteBatch may queue up
+	// for the background committer before blocking. Zero means
+	// DefaultPipelineDepth.
+	PipelineDepth int `toml:"pipeline_depth"`
+}
+
+// DefaultConfig returns the default store configuration: leveldb rooted at
+// "data".
+func DefaultConfig() *Config {
+	return &Config{
+		Path:          "data",
+		Backend:       LevelDBBackend,
+		PipelineDepth: DefaultPipelineDepth,
+	}
+}
+
+// StorePath returns the directory the backend should open, relative to the
+// node's working directory.
+func (conf *Config) StorePath() string {
+	return conf.Path
+}
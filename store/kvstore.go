@@ -0,0 +1,64 @@
+package store
+
+// Backend selects which KVBackend implementation NewStore opens.
+type Backend int
+
+const (
+	// LevelDBBackend is the default, disk-backed backend.
+	LevelDBBackend Backend = iota
+	// PebbleBackend is a disk-backed backend with better write
+	// throughput, useful for archival nodes.
+	PebbleBackend
+	// MemoryBackend keeps everything in memory. It never touches disk,
+	// which makes it a good fit for unit tests.
+	MemoryBackend
+)
+
+// KVBackend hides the concrete key-value engine (leveldb, pebble, an
+// in-memory map, ...) behind the handful of operations the store package
+// needs: point lookups, prefix scans, atomic batched writes, and
+// maintenance (Close/Compact).
+type KVBackend interface {
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	NewBatch() KVBatch
+	NewIterator(prefix []byte) KVIterator
+	Close() error
+	Compact() error
+}
+
+// KVBatch accumulates writes to be applied atomically by Write.
+type KVBatch interface {
+	Put(key, value []byte)
+	Delete(key []byte)
+	Reset()
+	Len() int
+	Write() error
+}
+
+// KVIterator scans the keys sharing the prefix passed to NewIterator, in
+// ascending key order. Call Next before the first Key/Value, and Release
+// once done with the iterator.
+type KVIterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// openBackend opens the KVBackend selected by conf.Backend.
+func openBackend(conf *Config) (KVBackend, error) {
+	switch conf.Backend {
+	case PebbleBackend:
+		return newPebbleBackend(conf.StorePath())
+	case MemoryBackend:
+		return newMemoryBackend(), nil
+	case LevelDBBackend:
+		return newLevelDBBackend(conf.StorePath())
+	default:
+		return newLevelDBBackend(conf.StorePath())
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBackendGetPutDelete(t *testing.T) {
+	backend := newMemoryBackend()
+	t.Cleanup(func() { assert.NoError(t, backend.Close()) })
+
+	_, err := backend.Get([]byte("k1"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	has, err := backend.Has([]byte("k1"))
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	assert.NoError(t, backend.Put([]byte("k1"), []byte("v1")))
+
+	value, err := backend.Get([]byte("k1"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+
+	has, err = backend.Has([]byte("k1"))
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	assert.NoError(t, backend.Delete([]byte("k1")))
+	_, err = backend.Get([]byte("k1"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryBackendBatch(t *testing.T) {
+	backend := newMemoryBackend()
+	t.Cleanup(func() { assert.NoError(t, backend.Close()) })
+
+	assert.NoError(t, backend.Put([]byte("stale"), []byte("x")))
+
+	batch := backend.NewBatch()
+	batch.Put([]byte("a"), []byte("1"))
+	batch.Put([]byte("b"), []byte("2"))
+	batch.Delete([]byte("stale"))
+	assert.Equal(t, 3, batch.Len())
+
+	// Nothing is visible until Write is called.
+	_, err := backend.Get([]byte("a"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, batch.Write())
+
+	value, err := backend.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	_, err = backend.Get([]byte("stale"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryBackendIteratorPrefix(t *testing.T) {
+	backend := newMemoryBackend()
+	t.Cleanup(func() { assert.NoError(t, backend.Close()) })
+
+	assert.NoError(t, backend.Put([]byte("acc-1"), []byte("a")))
+	assert.NoError(t, backend.Put([]byte("acc-2"), []byte("b")))
+	assert.NoError(t, backend.Put([]byte("val-1"), []byte("c")))
+
+	iter := backend.NewIterator([]byte("acc-"))
+	defer iter.Release()
+
+	keys := make([]string, 0, 2)
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	assert.NoError(t, iter.Error())
+	assert.Equal(t, []string{"acc-1", "acc-2"}, keys)
+}
+
+func TestNewStoreMemoryBackend(t *testing.T) {
+	conf := &Config{Backend: MemoryBackend}
+	s, err := NewStore(conf)
+	require.NoError(t, err)
+
+	sImpl, ok := s.(*store)
+	require.True(t, ok)
+	assert.IsType(t, &memoryBackend{}, sImpl.backend)
+
+	assert.NoError(t, s.Close())
+}
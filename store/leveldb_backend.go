@@ -0,0 +1,91 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// leveldbBackend is the default KVBackend implementation.
+type leveldbBackend struct {
+	db *leveldb.DB
+}
+
+func newLevelDBBackend(path string) (KVBackend, error) {
+	options := &opt.Options{
+		Strict:      opt.DefaultStrict,
+		Compression: opt.NoCompression,
+	}
+
+	db, err := leveldb.OpenFile(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &leveldbBackend{db: db}, nil
+}
+
+func (b *leveldbBackend) Get(key []byte) ([]byte, error) {
+	data, err := b.db.Get(key, nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (b *leveldbBackend) Has(key []byte) (bool, error) {
+	return b.db.Has(key, nil)
+}
+
+func (b *leveldbBackend) Put(key, value []byte) error {
+	return b.db.Put(key, value, nil)
+}
+
+func (b *leveldbBackend) Delete(key []byte) error {
+	return b.db.Delete(key, nil)
+}
+
+func (b *leveldbBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *leveldbBackend) Compact() error {
+	return b.db.CompactRange(util.Range{})
+}
+
+func (b *leveldbBackend) NewBatch() KVBatch {
+	return &leveldbBatch{db: b.db, batch: new(leveldb.Batch)}
+}
+
+func (b *leveldbBackend) NewIterator(prefix []byte) KVIterator {
+	return &leveldbIterator{iter: b.db.NewIterator(util.BytesPrefix(prefix), nil)}
+}
+
+type leveldbBatch struct {
+	db    *leveldb.DB
+	batch *leveldb.Batch
+}
+
+func (b *leveldbBatch) Put(key, value []byte) { b.batch.Put(key, value) }
+func (b *leveldbBatch) Delete(key []byte)     { b.batch.Delete(key) }
+func (b *leveldbBatch) Reset()                { b.batch.Reset() }
+func (b *leveldbBatch) Len() int              { return b.batch.Len() }
+func (b *leveldbBatch) Write() error          { return b.db.Write(b.batch, nil) }
+
+type leveldbIterator struct {
+	iter iterator.Iterator
+}
+
+func (it *leveldbIterator) Next() bool    { return it.iter.Next() }
+func (it *leveldbIterator) Key() []byte   { return it.iter.Key() }
+func (it *leveldbIterator) Value() []byte { return it.iter.Value() }
+func (it *leveldbIterator) Error() error  { return it.iter.Error() }
+func (it *leveldbIterator) Release()      { it.iter.Release() }
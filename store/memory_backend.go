@@ -0,0 +1,147 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// memoryBackend is a KVBackend that never touches disk, so tests can open a
+// store without a real leveldb directory.
+type memoryBackend struct {
+	lk   sync.RWMutex
+	data map[string][]byte
+}
+
+func newMemoryBackend() KVBackend {
+	return &memoryBackend{data: make(map[string][]byte)}
+}
+
+func (b *memoryBackend) Get(key []byte) ([]byte, error) {
+	b.lk.RLock()
+	defer b.lk.RUnlock()
+
+	value, ok := b.data[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	out := make([]byte, len(value))
+	copy(out, value)
+
+	return out, nil
+}
+
+func (b *memoryBackend) Has(key []byte) (bool, error) {
+	b.lk.RLock()
+	defer b.lk.RUnlock()
+
+	_, ok := b.data[string(key)]
+
+	return ok, nil
+}
+
+func (b *memoryBackend) Put(key, value []byte) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+
+	cp := make([]byte, len(value))
+	copy(cp, value)
+	b.data[string(key)] = cp
+
+	return nil
+}
+
+func (b *memoryBackend) Delete(key []byte) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+
+	delete(b.data, string(key))
+
+	return nil
+}
+
+func (b *memoryBackend) Close() error   { return nil }
+func (b *memoryBackend) Compact() error { return nil }
+
+func (b *memoryBackend) NewBatch() KVBatch {
+	return &memoryBatch{backend: b}
+}
+
+func (b *memoryBackend) NewIterator(prefix []byte) KVIterator {
+	b.lk.RLock()
+	defer b.lk.RUnlock()
+
+	keys := make([]string, 0, len(b.data))
+	for key := range b.data {
+		if strings.HasPrefix(key, string(prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return &memoryIterator{backend: b, keys: keys, pos: -1}
+}
+
+type memoryOp struct {
+	del   bool
+	key   string
+	value []byte
+}
+
+type memoryBatch struct {
+	backend *memoryBackend
+	ops     []memoryOp
+}
+
+func (b *memoryBatch) Put(key, value []byte) {
+	cp := make([]byte, len(value))
+	copy(cp, value)
+	b.ops = append(b.ops, memoryOp{key: string(key), value: cp})
+}
+
+func (b *memoryBatch) Delete(key []byte) {
+	b.ops = append(b.ops, memoryOp{del: true, key: string(key)})
+}
+
+func (b *memoryBatch) Reset() { b.ops = b.ops[:0] }
+func (b *memoryBatch) Len() int { return len(b.ops) }
+
+func (b *memoryBatch) Write() error {
+	b.backend.lk.Lock()
+	defer b.backend.lk.Unlock()
+
+	for _, op := range b.ops {
+		if op.del {
+			delete(b.backend.data, op.key)
+		} else {
+			b.backend.data[op.key] = op.value
+		}
+	}
+
+	return nil
+}
+
+type memoryIterator struct {
+	backend *memoryBackend
+	keys    []string
+	pos     int
+}
+
+func (it *memoryIterator) Next() bool {
+	it.pos++
+
+	return it.pos < len(it.keys)
+}
+
+func (it *memoryIterator) Key() []byte { return []byte(it.keys[it.pos]) }
+
+func (it *memoryIterator) Value() []byte {
+	it.backend.lk.RLock()
+	defer it.backend.lk.RUnlock()
+
+	return it.backend.data[it.keys[it.pos]]
+}
+
+func (it *memoryIterator) Error() error { return nil }
+func (it *memoryIterator) Release()     {}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// pebbleBackend trades leveldb's single-writer-goroutine model for pebble's
+// LSM tree, which gives better write throughput on archival nodes that
+// replay long chain histories.
+type pebbleBackend struct {
+	db *pebble.DB
+}
+
+func newPebbleBackend(path string) (KVBackend, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pebbleBackend{db: db}, nil
+}
+
+func (b *pebbleBackend) Get(key []byte) ([]byte, error) {
+	value, closer, err := b.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+	defer closer.Close()
+
+	out := make([]byte, len(value))
+	copy(out, value)
+
+	return out, nil
+}
+
+func (b *pebbleBackend) Has(key []byte) (bool, error) {
+	_, closer, err := b.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer closer.Close()
+
+	return true, nil
+}
+
+func (b *pebbleBackend) Put(key, value []byte) error {
+	return b.db.Set(key, value, pebble.Sync)
+}
+
+func (b *pebbleBackend) Delete(key []byte) error {
+	return b.db.Delete(key, pebble.Sync)
+}
+
+func (b *pebbleBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *pebbleBackend) Compact() error {
+	start, end := []byte{0x00}, bytes.Repeat([]byte{0xff}, 32)
+
+	return b.db.Compact(start, end, true)
+}
+
+func (b *pebbleBackend) NewBatch() KVBatch {
+	return &pebbleBatch{batch: b.db.NewBatch()}
+}
+
+func (b *pebbleBackend) NewIterator(prefix []byte) KVIterator {
+	iter, _ := b.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+
+	return &pebbleIterator{iter: iter, started: false}
+}
+
+// prefixUpperBound returns the smallest key that sorts after every key
+// sharing prefix, bounding a prefix scan.
+func prefixUpperBound(prefix []byte) []byte {
+	upper := make([]byte, len(prefix))
+	copy(upper, prefix)
+
+	for i := len(upper) - 1; i >= 0; i-- {
+		upper[i]++
+		if upper[i] != 0 {
+			return upper[:i+1]
+		}
+	}
+
+	return nil // prefix is all 0xff: no upper bound needed
+}
+
+type pebbleBatch struct {
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Put(key, value []byte) { _ = b.batch.Set(key, value, nil) }
+func (b *pebbleBatch) Delete(key []byte)     { _ = b.batch.Delete(key, nil) }
+func (b *pebbleBatch) Reset()                { b.batch.Reset() }
+func (b *pebbleBatch) Len() int              { return b.batch.Count() }
+func (b *pebbleBatch) Write() error          { return b.batch.Commit(pebble.Sync) }
+
+type pebbleIterator struct {
+	iter    *pebble.Iterator
+	started bool
+}
+
+func (it *pebbleIterator) Next() bool {
+	if !it.started {
+		it.started = true
+
+		return it.iter.First()
+	}
+
+	return it.iter.Next()
+}
+
+func (it *pebbleIterator) Key() []byte   { return it.iter.Key() }
+func (it *pebbleIterator) Value() []byte { return it.iter.Value() }
+func (it *pebbleIterator) Error() error  { return it.iter.Error() }
+func (it *pebbleIterator) Release()      { _ = it.iter.Close() }
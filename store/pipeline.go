@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pactus-project/pactus/types/certificate"
+	"github.com/pactus-project/pactus/util/logger"
+)
+
+// ErrPipelineClosed is returned by Enqueue, and so by WriteBatch, once the
+// store's commit pipeline has been closed.
+var ErrPipelineClosed = errors.New("store commit pipeline is closed")
+
+// ErrPipelineHalted is returned by WriteBatch once a previously queued batch
+// has failed to commit. A failed batch means the backend is missing that
+// block's data, so committing any batch queued after it would durably write
+// a later block's state on top of a gap; the pipeline refuses to do that and
+// must be recreated (i.e. the store restarted) instead.
+var ErrPipelineHalted = errors.New("store commit pipeline halted after a commit failure")
+
+// DefaultPipelineDepth bounds how many built batches WriteBatch may queue
+// up before the background committer has caught up. Once the queue is
+// full, WriteBatch blocks until a slot frees up.
+const DefaultPipelineDepth = 2
+
+// pendingCommit is a fully-built batch plus a snapshot of the in-memory
+// counters that must move in lockstep with it. It's handed off to the
+// background committer so the next block can start executing while this
+// one is still being written to disk.
+type pendingCommit struct {
+	batch           KVBatch
+	height          uint32
+	cert            *certificate.Certificate
+	accountsTotal   int32
+	validatorsTotal int32
+
+	// accountKeys/validatorKeys are the overlay keys accountStore/
+	// validatorStore staged for this batch. onCommitComplete is called
+	// with them once the batch's commit is resolved (written or abandoned),
+	// so the overlay can stop shadowing the backend for those keys.
+	accountKeys   []pendingKey
+	validatorKeys []pendingKey
+}
+
+// pipeline applies SaveBlock's batches to a KVBackend on a background
+// goroutine, so db.Write for height N can overlap with executing block
+// N+1. It is modeled on the state-verification/snapshot-commit pipelines
+// used by other chains: the caller builds a complete, self-consistent
+// snapshot (batch + counters) and only that snapshot crosses the goroutine
+// boundary.
+type pipeline struct {
+	queue     chan *pendingCommit
+	itemsWG   sync.WaitGroup
+	runWG     sync.WaitGroup
+	closeOnce sync.Once
+
+	// closeLk guards closed: Enqueue holds it for read for the whole
+	// check-then-send, and Close holds it for write around setting closed
+	// and closing queue, so a concurrent Enqueue can never observe closed
+	// as false and then send on an already-closed queue.
+	closeLk sync.RWMutex
+	closed  bool
+
+	commitLk        sync.RWMutex
+	lastCert        *certificate.Certificate
+	lastErr         error
+	halted          bool
+	accountsTotal   int32
+	validatorsTotal int32
+
+	// onCommitFailure reconciles the store's in-memory counters back to
+	// accountsTotal/validatorsTotal (the totals as of the last batch that
+	// was actually written) when a queued commit fails, since SaveBlock
+	// already applied the failed batch's counters in memory before it was
+	// ever handed to the pipeline.
+	onCommitFailure func(accountsTotal, validatorsTotal int32)
+
+	// onCommitComplete is called after every commit attempt, successful or
+	// not, with the overlay keys that batch staged, so accountStore and
+	// validatorStore can stop shadowing the backend for them.
+	onCommitComplete func(accountKeys, validatorKeys []pendingKey)
+
+	queueDepth    int64 // atomic
+	commitLatency int64 // atomic, nanoseconds of the most recently completed commit
+}
+
+// newPipeline starts the background committer. depth <= 0 falls back to
+// DefaultPipelineDepth. lastCert is the certificate already durable in the
+// backend at startup (nil for a fresh store). onCommitFailure, if non-nil,
+// is called with the last durably-committed counters whenever a queued
+// batch fails to write, so the caller can roll its in-memory totals back.
+// onCommitComplete, if non-nil, is called after every commit attempt with
+// the keys that batch staged in accountStore/validatorStore's overlays.
+func newPipeline(
+	depth int, lastCert *certificate.Certificate,
+	onCommitFailure func(accountsTotal, validatorsTotal int32),
+	onCommitComplete func(accountKeys, validatorKeys []pendingKey),
+) *pipeline {
+	if depth <= 0 {
+		depth = DefaultPipelineDepth
+	}
+
+	p := &pipeline{
+		queue:            make(chan *pendingCommit, depth),
+		lastCert:         lastCert,
+		onCommitFailure:  onCommitFailure,
+		onCommitComplete: onCommitComplete,
+	}
+
+	p.runWG.Add(1)
+	go p.run()
+
+	return p
+}
+
+func (p *pipeline) run() {
+	defer p.runWG.Done()
+
+	for commit := range p.queue {
+		p.commitLk.RLock()
+		halted := p.halted
+		p.commitLk.RUnlock()
+
+		var err error
+		if halted {
+			// A previous batch in this queue already failed: the backend is
+			// missing that block's data, so writing this one would durably
+			// commit a later block's state on top of a gap. Treat it as
+			// failed too without ever calling batch.Write().
+			err = ErrPipelineHalted
+		} else {
+			start := time.Now()
+			err = commit.batch.Write()
+			atomic.StoreInt64(&p.commitLatency, int64(time.Since(start)))
+		}
+		atomic.AddInt64(&p.queueDepth, -1)
+
+		p.commitLk.Lock()
+		if err != nil {
+			logger.Error("failed to commit batch", "error", err, "height", commit.height,
+				"accounts", commit.accountsTotal, "validators", commit.validatorsTotal)
+			p.lastErr = err
+			p.halted = true
+			accountsTotal, validatorsTotal := p.accountsTotal, p.validatorsTotal
+			p.commitLk.Unlock()
+
+			if p.onCommitFailure != nil {
+				p.onCommitFailure(accountsTotal, validatorsTotal)
+			}
+		} else {
+			p.lastCert = commit.cert
+			p.accountsTotal = commit.accountsTotal
+			p.validatorsTotal = commit.validatorsTotal
+			p.commitLk.Unlock()
+		}
+
+		if p.onCommitComplete != nil {
+			p.onCommitComplete(commit.accountKeys, commit.validatorKeys)
+		}
+
+		p.itemsWG.Done()
+	}
+}
+
+// Enqueue hands commit to the background committer, blocking if the queue
+// is already at its configured depth.
+func (p *pipeline) Enqueue(commit *pendingCommit) error {
+	p.closeLk.RLock()
+	defer p.closeLk.RUnlock()
+
+	if p.closed {
+		return ErrPipelineClosed
+	}
+
+	p.commitLk.RLock()
+	halted := p.halted
+	p.commitLk.RUnlock()
+	if halted {
+		return ErrPipelineHalted
+	}
+
+	p.itemsWG.Add(1)
+	atomic.AddInt64(&p.queueDepth, 1)
+	p.queue <- commit
+
+	return nil
+}
+
+// QueueDepth returns the number of batches queued or currently being
+// written.
+func (p *pipeline) QueueDepth() int64 {
+	return atomic.LoadInt64(&p.queueDepth)
+}
+
+// CommitLatency returns the duration of the most recently completed
+// commit.
+func (p *pipeline) CommitLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.commitLatency))
+}
+
+// LastCertificate returns the certificate of the last batch durably
+// written to the backend.
+func (p *pipeline) LastCertificate() *certificate.Certificate {
+	p.commitLk.RLock()
+	defer p.commitLk.RUnlock()
+
+	return p.lastCert
+}
+
+// Flush blocks until every queued batch has been committed, or ctx is
+// canceled, then returns the most recent commit error (nil if none).
+func (p *pipeline) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.itemsWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	p.commitLk.RLock()
+	defer p.commitLk.RUnlock()
+
+	return p.lastErr
+}
+
+// Close flushes the pipeline and stops the background committer. The
+// pipeline must not be used afterwards.
+func (p *pipeline) Close(ctx context.Context) error {
+	err := p.Flush(ctx)
+
+	p.closeOnce.Do(func() {
+		p.closeLk.Lock()
+		p.closed = true
+		close(p.queue)
+		p.closeLk.Unlock()
+	})
+	p.runWG.Wait()
+
+	return err
+}
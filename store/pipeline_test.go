@@ -0,0 +1,265 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/account"
+	"github.com/pactus-project/pactus/types/certificate"
+	"github.com/pactus-project/pactus/types/validator"
+	"github.com/pactus-project/pactus/util/testsuite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineHammerConcurrentCommits(t *testing.T) {
+	backend := newMemoryBackend()
+	p := newPipeline(4, nil, nil, nil)
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			batch := backend.NewBatch()
+			batch.Put([]byte(fmt.Sprintf("key-%d", i)), []byte("v"))
+			require.NoError(t, p.Enqueue(&pendingCommit{
+				batch:  batch,
+				height: uint32(i),
+				cert:   &certificate.Certificate{},
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, p.Flush(context.Background()))
+
+	for i := 0; i < n; i++ {
+		_, err := backend.Get([]byte(fmt.Sprintf("key-%d", i)))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, p.Close(context.Background()))
+}
+
+// TestPipelineCloseDrainsQueueOnShutdown simulates a shutdown that races
+// ahead of the background committer: Close is given an already-canceled
+// context, so Flush can't wait for the queue to drain. Close must still
+// drain every already-queued commit before it returns, so no durable write
+// is silently lost.
+func TestPipelineCloseDrainsQueueOnShutdown(t *testing.T) {
+	backend := newMemoryBackend()
+	p := newPipeline(8, nil, nil, nil)
+
+	const n = 5
+	for i := 0; i < n; i++ {
+		batch := backend.NewBatch()
+		batch.Put([]byte(fmt.Sprintf("k-%d", i)), []byte("v"))
+		require.NoError(t, p.Enqueue(&pendingCommit{
+			batch:  batch,
+			height: uint32(i),
+			cert:   &certificate.Certificate{},
+		}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := p.Close(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	for i := 0; i < n; i++ {
+		_, err := backend.Get([]byte(fmt.Sprintf("k-%d", i)))
+		assert.NoError(t, err, "queued commit %d must not be lost on shutdown", i)
+	}
+}
+
+// TestPipelineEnqueueDuringCloseDoesNotPanic races Enqueue against Close:
+// it must never send on the queue after Close has closed it.
+func TestPipelineEnqueueDuringCloseDoesNotPanic(t *testing.T) {
+	backend := newMemoryBackend()
+	p := newPipeline(1, nil, nil, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			batch := backend.NewBatch()
+			batch.Put([]byte(fmt.Sprintf("race-%d", i)), []byte("v"))
+			_ = p.Enqueue(&pendingCommit{batch: batch, height: uint32(i), cert: &certificate.Certificate{}})
+		}(i)
+	}
+
+	assert.NoError(t, p.Close(context.Background()))
+	wg.Wait()
+}
+
+type failingBatch struct {
+	inner KVBatch
+}
+
+func (b *failingBatch) Put(key, value []byte) { b.inner.Put(key, value) }
+func (b *failingBatch) Delete(key []byte)     { b.inner.Delete(key) }
+func (b *failingBatch) Reset()                { b.inner.Reset() }
+func (b *failingBatch) Len() int              { return b.inner.Len() }
+func (b *failingBatch) Write() error          { return errors.New("simulated commit failure") }
+
+// TestPipelineReconcilesTotalsOnCommitFailure confirms that a failed commit
+// rolls the caller's counters back to the last batch that actually landed,
+// not just logs the mismatch.
+func TestPipelineReconcilesTotalsOnCommitFailure(t *testing.T) {
+	backend := newMemoryBackend()
+	gotAccounts, gotValidators := int32(-1), int32(-1)
+	p := newPipeline(2, nil, func(accountsTotal, validatorsTotal int32) {
+		gotAccounts, gotValidators = accountsTotal, validatorsTotal
+	}, nil)
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+
+	require.NoError(t, p.Enqueue(&pendingCommit{
+		batch: backend.NewBatch(), height: 1, cert: &certificate.Certificate{},
+		accountsTotal: 3, validatorsTotal: 1,
+	}))
+	require.NoError(t, p.Flush(context.Background()))
+
+	require.NoError(t, p.Enqueue(&pendingCommit{
+		batch: &failingBatch{inner: backend.NewBatch()}, height: 2, cert: &certificate.Certificate{},
+		accountsTotal: 5, validatorsTotal: 2,
+	}))
+	assert.Error(t, p.Flush(context.Background()))
+
+	assert.Equal(t, int32(3), gotAccounts)
+	assert.Equal(t, int32(1), gotValidators)
+}
+
+// TestPipelineHaltsAfterCommitFailure confirms that once a queued batch
+// fails to commit, the pipeline refuses every batch queued after it instead
+// of writing it on top of the resulting gap, and that it keeps refusing new
+// batches even after the failure (a failure as the last queued item, as in
+// TestPipelineReconcilesTotalsOnCommitFailure, would never exercise this).
+func TestPipelineHaltsAfterCommitFailure(t *testing.T) {
+	backend := newMemoryBackend()
+	p := newPipeline(4, nil, nil, nil)
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+
+	require.NoError(t, p.Enqueue(&pendingCommit{
+		batch: &failingBatch{inner: backend.NewBatch()}, height: 1, cert: &certificate.Certificate{},
+	}))
+
+	goodBatch := backend.NewBatch()
+	goodBatch.Put([]byte("k"), []byte("v"))
+	require.NoError(t, p.Enqueue(&pendingCommit{batch: goodBatch, height: 2, cert: &certificate.Certificate{}}))
+
+	assert.Error(t, p.Flush(context.Background()))
+
+	// The batch queued after the failure must never have been written.
+	_, err := backend.Get([]byte("k"))
+	assert.Error(t, err)
+
+	// The pipeline must keep refusing new batches after the halt.
+	err = p.Enqueue(&pendingCommit{batch: backend.NewBatch(), height: 3, cert: &certificate.Certificate{}})
+	assert.ErrorIs(t, err, ErrPipelineHalted)
+}
+
+// TestPipelineOnCommitCompleteEvictsOverlayOnBothOutcomes confirms
+// onCommitComplete fires with the batch's overlay keys whether the commit
+// succeeded or failed, so accountStore/validatorStore never shadow the
+// backend forever for a resolved batch.
+func TestPipelineOnCommitCompleteEvictsOverlayOnBothOutcomes(t *testing.T) {
+	backend := newMemoryBackend()
+
+	var mu sync.Mutex
+	var completed [][]pendingKey
+	p := newPipeline(4, nil, nil, func(accountKeys, _ []pendingKey) {
+		mu.Lock()
+		completed = append(completed, accountKeys)
+		mu.Unlock()
+	})
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+
+	require.NoError(t, p.Enqueue(&pendingCommit{
+		batch: backend.NewBatch(), height: 1, cert: &certificate.Certificate{},
+		accountKeys: []pendingKey{{key: "ok", seq: 1}},
+	}))
+	require.NoError(t, p.Flush(context.Background()))
+
+	require.NoError(t, p.Enqueue(&pendingCommit{
+		batch: &failingBatch{inner: backend.NewBatch()}, height: 2, cert: &certificate.Certificate{},
+		accountKeys: []pendingKey{{key: "failed", seq: 2}},
+	}))
+	assert.Error(t, p.Flush(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, [][]pendingKey{{{key: "ok", seq: 1}}, {{key: "failed", seq: 2}}}, completed)
+}
+
+func TestPipelineLastCertificateReflectsLatestCommit(t *testing.T) {
+	backend := newMemoryBackend()
+	p := newPipeline(2, nil, nil, nil)
+	t.Cleanup(func() { assert.NoError(t, p.Close(context.Background())) })
+
+	assert.Nil(t, p.LastCertificate())
+
+	batch := backend.NewBatch()
+	batch.Put([]byte("a"), []byte("1"))
+	cert := &certificate.Certificate{}
+	require.NoError(t, p.Enqueue(&pendingCommit{batch: batch, height: 7, cert: cert}))
+
+	require.NoError(t, p.Flush(context.Background()))
+	assert.Same(t, cert, p.LastCertificate())
+}
+
+// TestIterateAccountsSeesQueuedCommit confirms IterateAccounts (and thus
+// ValidatorAddresses/IterateValidators below) reflect a batch that's been
+// staged but whose commit is still sitting in the pipeline queue, the same
+// overlay guarantee account/hasAccount already give point reads.
+func TestIterateAccountsSeesQueuedCommit(t *testing.T) {
+	ts := testsuite.NewTestSuite(t)
+
+	s, err := NewStore(&Config{Backend: MemoryBackend})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, s.Close()) })
+
+	pub, _ := ts.RandBLSKeyPair()
+	addr := pub.AccountAddress()
+	acc := account.NewAccount(1)
+	acc.AddToBalance(1000)
+
+	s.UpdateAccount(addr, acc)
+	require.NoError(t, s.WriteBatch())
+
+	// The batch is queued (and may still be in flight), not yet flushed.
+	var got *account.Account
+	s.IterateAccounts(func(a crypto.Address, found *account.Account) bool {
+		if a == addr {
+			got = found
+		}
+
+		return false
+	})
+	require.NotNil(t, got)
+	assert.Equal(t, acc.Balance(), got.Balance())
+
+	val := validator.NewValidator(pub, 1)
+	s.UpdateValidator(val)
+	require.NoError(t, s.WriteBatch())
+
+	var gotAddrs []crypto.Address
+	s.IterateValidators(func(v *validator.Validator) bool {
+		gotAddrs = append(gotAddrs, v.Address())
+
+		return false
+	})
+	assert.Contains(t, gotAddrs, val.Address())
+	assert.Contains(t, s.ValidatorAddresses(), val.Address())
+
+	require.NoError(t, s.Flush(context.Background()))
+}
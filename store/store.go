@@ -2,8 +2,10 @@ package store
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/pactus-project/pactus/crypto"
 	"github.com/pactus-project/pactus/crypto/bls"
@@ -16,8 +18,6 @@ import (
 	"github.com/pactus-project/pactus/util"
 	"github.com/pactus-project/pactus/util/encoding"
 	"github.com/pactus-project/pactus/util/logger"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/opt"
 )
 
 var (
@@ -39,8 +39,23 @@ var (
 	publicKeyPrefix   = []byte{0x0a}
 )
 
-func tryGet(db *leveldb.DB, key []byte) ([]byte, error) {
-	data, err := db.Get(key, nil)
+// publicKeyKey is the key a validator's BLS public key is stored under, the
+// first time an address with a known public key is seen.
+func publicKeyKey(addr crypto.Address) []byte {
+	return append(append([]byte{}, publicKeyPrefix...), addr.Bytes()...)
+}
+
+// pendingKey identifies one write staged into accountStore's or
+// validatorStore's overlay: seq lets evictPending tell its own write apart
+// from a newer one that has since overwritten the same key, so evicting an
+// older, already-resolved batch never discards a newer, still in-flight one.
+type pendingKey struct {
+	key string
+	seq uint64
+}
+
+func tryGet(backend KVBackend, key []byte) ([]byte, error) {
+	data, err := backend.Get(key)
 	if err != nil {
 		// Probably key doesn't exist in database
 		logger.Trace("database error", "error", err, "key", key)
@@ -53,8 +68,11 @@ type store struct {
 	lk sync.RWMutex
 
 	config         *Config
-	db             *leveldb.DB
-	batch          *leveldb.Batch
+	backend        KVBackend
+	batch          KVBatch
+	pendingCert    *certificate.Certificate
+	pendingHeight  uint32
+	pipeline       *pipeline
 	blockStore     *blockStore
 	txStore        *txStore
 	accountStore   *accountStore
@@ -62,31 +80,93 @@ type store struct {
 }
 
 func NewStore(conf *Config) (Store, error) {
-	options := &opt.Options{
-		Strict:      opt.DefaultStrict,
-		Compression: opt.NoCompression,
-	}
-	db, err := leveldb.OpenFile(conf.StorePath(), options)
+	backend, err := openBackend(conf)
 	if err != nil {
 		return nil, err
 	}
+
+	lastCert := readLastCertificate(backend)
+
 	s := &store{
 		config:         conf,
-		db:             db,
-		batch:          new(leveldb.Batch),
-		blockStore:     newBlockStore(db),
-		txStore:        newTxStore(db),
-		accountStore:   newAccountStore(db),
-		validatorStore: newValidatorStore(db),
+		backend:        backend,
+		batch:          backend.NewBatch(),
+		blockStore:     newBlockStore(backend),
+		txStore:        newTxStore(backend),
+		accountStore:   newAccountStore(backend),
+		validatorStore: newValidatorStore(backend),
 	}
+	s.pipeline = newPipeline(conf.PipelineDepth, lastCert, s.reconcileTotals, s.evictPendingOverlays)
+
+	// Seed the pipeline with the totals already durable in the backend, so a
+	// failure of the first queued commit rolls back to them rather than to 0.
+	s.pipeline.commitLk.Lock()
+	s.pipeline.accountsTotal = s.accountStore.total
+	s.pipeline.validatorsTotal = s.validatorStore.total
+	s.pipeline.commitLk.Unlock()
+
 	return s, nil
 }
 
+// reconcileTotals rolls the in-memory account/validator counters back to
+// accountsTotal/validatorsTotal after a queued batch fails to commit:
+// SaveBlock already applied that batch's counters in memory before handing
+// it to the pipeline, so a failed write would otherwise leave them ahead of
+// what's durable on the backend.
+func (s *store) reconcileTotals(accountsTotal, validatorsTotal int32) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	s.accountStore.total = accountsTotal
+	s.validatorStore.total = validatorsTotal
+}
+
+// evictPendingOverlays drops accountKeys/validatorKeys from accountStore's
+// and validatorStore's overlays once the batch that staged them has been
+// resolved by the pipeline, durably written or not.
+func (s *store) evictPendingOverlays(accountKeys, validatorKeys []pendingKey) {
+	s.accountStore.evictPending(accountKeys)
+	s.validatorStore.evictPending(validatorKeys)
+}
+
 func (s *store) Close() error {
+	if err := s.pipeline.Close(context.Background()); err != nil {
+		logger.Error("error while flushing store on close", "error", err)
+	}
+
 	s.lk.Lock()
 	defer s.lk.Unlock()
 
-	return s.db.Close()
+	return s.backend.Close()
+}
+
+// Flush blocks until every batch queued by WriteBatch has been committed to
+// the backend, or ctx is canceled. Call it before Close, and before serving
+// reads that must reflect the latest committed height.
+func (s *store) Flush(ctx context.Context) error {
+	return s.pipeline.Flush(ctx)
+}
+
+// PendingHeight returns the height of the most recently saved block, whether
+// or not WriteBatch has queued its batch yet or it is durable, so consensus
+// can gate reorgs on it.
+func (s *store) PendingHeight() uint32 {
+	s.lk.RLock()
+	defer s.lk.RUnlock()
+
+	return s.pendingHeight
+}
+
+// QueueDepth returns the number of batches queued or being written by the
+// commit pipeline.
+func (s *store) QueueDepth() int64 {
+	return s.pipeline.QueueDepth()
+}
+
+// CommitLatency returns the duration of the most recently completed
+// pipeline commit.
+func (s *store) CommitLatency() time.Duration {
+	return s.pipeline.CommitLatency()
 }
 
 func (s *store) SaveBlock(blk *block.Block, cert *certificate.Certificate) {
@@ -111,6 +191,9 @@ func (s *store) SaveBlock(blk *block.Block, cert *certificate.Certificate) {
 	}
 
 	s.batch.Put(lastInfoKey, w.Bytes())
+
+	s.pendingCert = cert
+	s.pendingHeight = height
 }
 
 func (s *store) Block(height uint32) (*CommittedBlock, error) {
@@ -156,7 +239,7 @@ func (s *store) BlockHash(height uint32) hash.Hash {
 }
 
 func (s *store) PublicKey(addr crypto.Address) (*bls.PublicKey, error) {
-	bs, err := tryGet(s.db, publicKeyKey(addr))
+	bs, err := tryGet(s.backend, publicKeyKey(addr))
 	if err != nil {
 		return nil, err
 	}
@@ -290,11 +373,17 @@ func (s *store) UpdateValidator(acc *validator.Validator) {
 	s.validatorStore.updateValidator(s.batch, acc)
 }
 
+// LastCertificate returns the certificate of the last block that was
+// durably written to the backend. Unlike PendingHeight, it never reflects a
+// batch that's still sitting in the commit pipeline.
 func (s *store) LastCertificate() *certificate.Certificate {
-	s.lk.Lock()
-	defer s.lk.Unlock()
+	return s.pipeline.LastCertificate()
+}
 
-	data, _ := tryGet(s.db, lastInfoKey)
+// readLastCertificate reads whatever certificate is already durable in
+// backend, used once at startup to seed the pipeline's cache.
+func readLastCertificate(backend KVBackend) *certificate.Certificate {
+	data, _ := tryGet(backend, lastInfoKey)
 	if data == nil {
 		// Genesis block
 		return nil
@@ -313,15 +402,32 @@ func (s *store) LastCertificate() *certificate.Certificate {
 	return cert
 }
 
+// WriteBatch hands the batch accumulated by SaveBlock to the commit
+// pipeline and immediately returns, so the next block can start executing
+// while this batch is written to the backend in the background. Use Flush
+// to wait for it to become durable.
 func (s *store) WriteBatch() error {
 	s.lk.Lock()
-	defer s.lk.Unlock()
+	batch := s.batch
+	s.batch = s.backend.NewBatch()
+	commit := &pendingCommit{
+		batch:           batch,
+		height:          s.pendingHeight,
+		cert:            s.pendingCert,
+		accountsTotal:   s.accountStore.total,
+		validatorsTotal: s.validatorStore.total,
+		accountKeys:     s.accountStore.takeStaged(),
+		validatorKeys:   s.validatorStore.takeStaged(),
+	}
+	s.lk.Unlock()
 
-	if err := s.db.Write(s.batch, nil); err != nil {
-		// TODO: Should we panic here?
-		// The store is unreliable if the stored data does not match the cached data.
-		return err
+	err := s.pipeline.Enqueue(commit)
+	if err != nil {
+		// The batch was never queued, so it will never be durably written
+		// or resolved by the pipeline: evict its overlay entries here
+		// instead, or they'd shadow the backend forever.
+		s.evictPendingOverlays(commit.accountKeys, commit.validatorKeys)
 	}
-	s.batch.Reset()
-	return nil
+
+	return err
 }
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"github.com/pactus-project/pactus/types/tx"
+	"github.com/pactus-project/pactus/util"
+)
+
+// txPos locates a transaction inside the value blockStore stored for
+// pos.height: the byte range [offset, offset+length) of that value.
+type txPos struct {
+	height uint32
+	offset uint32
+	length uint32
+}
+
+const txPosSize = 4 + 4 + 4
+
+// txStore indexes every transaction's position under txPrefix, keyed by
+// transaction ID, so Transaction and AnyRecentTransaction can find it
+// without scanning blocks.
+type txStore struct {
+	backend KVBackend
+}
+
+func newTxStore(backend KVBackend) *txStore {
+	return &txStore{backend: backend}
+}
+
+func txKey(id tx.ID) []byte {
+	return append(append([]byte{}, txPrefix...), id.Bytes()...)
+}
+
+func (s *txStore) saveTx(batch KVBatch, id tx.ID, pos *txPos) {
+	data := make([]byte, 0, txPosSize)
+	data = append(data, util.Uint32ToSlice(pos.height)...)
+	data = append(data, util.Uint32ToSlice(pos.offset)...)
+	data = append(data, util.Uint32ToSlice(pos.length)...)
+
+	batch.Put(txKey(id), data)
+}
+
+func (s *txStore) tx(id tx.ID) (*txPos, error) {
+	data, err := tryGet(s.backend, txKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != txPosSize {
+		return nil, ErrNotFound
+	}
+
+	return &txPos{
+		height: util.SliceToUint32(data[0:4]),
+		offset: util.SliceToUint32(data[4:8]),
+		length: util.SliceToUint32(data[8:12]),
+	}, nil
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/types/validator"
+)
+
+// validatorStore persists validators under validatorPrefix, keyed by
+// address, and records each validator's public key under publicKeyPrefix
+// the first time it's seen. total is kept in memory so TotalValidators
+// doesn't need a full scan.
+//
+// pending overlays validators that have been staged into a batch the commit
+// pipeline hasn't durably written yet, so hasValidator/validator see a
+// block's own writes (and a later block's, if both are still in flight)
+// instead of stale backend data.
+type validatorStore struct {
+	backend KVBackend
+	total   int32
+
+	pendingMu sync.RWMutex
+	pending   map[string]pendingEntry
+	staged    []pendingKey
+	nextSeq   uint64
+}
+
+func newValidatorStore(backend KVBackend) *validatorStore {
+	s := &validatorStore{backend: backend, pending: make(map[string]pendingEntry)}
+
+	it := backend.NewIterator(validatorPrefix)
+	defer it.Release()
+	for it.Next() {
+		s.total++
+	}
+
+	return s
+}
+
+func validatorKey(addr crypto.Address) []byte {
+	return append(append([]byte{}, validatorPrefix...), addr.Bytes()...)
+}
+
+func (s *validatorStore) hasValidator(addr crypto.Address) bool {
+	key := validatorKey(addr)
+
+	s.pendingMu.RLock()
+	_, pending := s.pending[string(key)]
+	s.pendingMu.RUnlock()
+	if pending {
+		return true
+	}
+
+	ok, _ := s.backend.Has(key)
+
+	return ok
+}
+
+func (s *validatorStore) validator(addr crypto.Address) (*validator.Validator, error) {
+	key := validatorKey(addr)
+
+	s.pendingMu.RLock()
+	entry, pending := s.pending[string(key)]
+	s.pendingMu.RUnlock()
+
+	data := entry.data
+	if !pending {
+		var err error
+		data, err = tryGet(s.backend, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return decodeValidator(data)
+}
+
+// validatorByNumber scans every validator, merging in the pending overlay so
+// a block's own writes (or a later block's, if both are still in flight) are
+// found even before their batch is durable.
+func (s *validatorStore) validatorByNumber(num int32) (*validator.Validator, error) {
+	var found *validator.Validator
+	s.iterateValidators(func(val *validator.Validator) bool {
+		if val.Number() == num {
+			found = val
+
+			return true
+		}
+
+		return false
+	})
+
+	if found == nil {
+		return nil, ErrNotFound
+	}
+
+	return found, nil
+}
+
+// iterateValidators walks every validator, merging in the pending overlay so
+// a block's own writes (or a later block's, if both are still in flight)
+// show up even before their batch is durable.
+func (s *validatorStore) iterateValidators(consumer func(*validator.Validator) (stop bool)) {
+	s.pendingMu.RLock()
+	pending := make(map[string][]byte, len(s.pending))
+	for key, entry := range s.pending {
+		pending[key] = entry.data
+	}
+	s.pendingMu.RUnlock()
+
+	seen := make(map[string]bool, len(pending))
+
+	it := s.backend.NewIterator(validatorPrefix)
+	defer it.Release()
+
+	for it.Next() {
+		key := string(it.Key())
+		data := it.Value()
+		if overlay, ok := pending[key]; ok {
+			data = overlay
+		}
+		seen[key] = true
+
+		val, err := decodeValidator(data)
+		if err != nil {
+			continue
+		}
+
+		if consumer(val) {
+			return
+		}
+	}
+
+	for key, data := range pending {
+		if seen[key] {
+			continue
+		}
+
+		val, err := decodeValidator(data)
+		if err != nil {
+			continue
+		}
+
+		if consumer(val) {
+			return
+		}
+	}
+}
+
+func (s *validatorStore) updateValidator(batch KVBatch, val *validator.Validator) {
+	addr := val.Address()
+	if !s.hasValidator(addr) {
+		s.total++
+		batch.Put(publicKeyKey(addr), val.PublicKey().Bytes())
+	}
+
+	w := new(bytes.Buffer)
+	if err := val.Encode(w); err != nil {
+		panic(err)
+	}
+
+	key := validatorKey(addr)
+	batch.Put(key, w.Bytes())
+
+	s.pendingMu.Lock()
+	s.nextSeq++
+	seq := s.nextSeq
+	s.pending[string(key)] = pendingEntry{data: w.Bytes(), seq: seq}
+	s.staged = append(s.staged, pendingKey{key: string(key), seq: seq})
+	s.pendingMu.Unlock()
+}
+
+// takeStaged returns and clears the validator keys written since the last
+// call, so WriteBatch can hand them to the pipeline: once the batch they
+// ended up in is durable (or abandoned after a commit failure), the
+// pipeline calls evictPending with this same list.
+func (s *validatorStore) takeStaged() []pendingKey {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	staged := s.staged
+	s.staged = nil
+
+	return staged
+}
+
+// evictPending drops keys from the overlay once the batch that staged them
+// has been resolved, durably written or not. A key is only dropped if its
+// entry is still the one this exact write staged; a newer write to the same
+// key (from a later, still in-flight batch) is left alone.
+func (s *validatorStore) evictPending(keys []pendingKey) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	for _, pk := range keys {
+		if entry, ok := s.pending[pk.key]; ok && entry.seq == pk.seq {
+			delete(s.pending, pk.key)
+		}
+	}
+}
+
+// ValidatorAddresses returns the address of every tracked validator.
+func (s *validatorStore) ValidatorAddresses() []crypto.Address {
+	addrs := make([]crypto.Address, 0, s.total)
+	s.iterateValidators(func(val *validator.Validator) bool {
+		addrs = append(addrs, val.Address())
+
+		return false
+	})
+
+	return addrs
+}
+
+func decodeValidator(data []byte) (*validator.Validator, error) {
+	val := new(validator.Validator)
+	if err := val.Decode(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
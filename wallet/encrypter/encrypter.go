@@ -0,0 +1,214 @@
+// Package encrypter implements the password-based encryption used to
+// protect the wallet's key store. Keys are derived with Argon2id, or with
+// scrypt for keystore-v3 interop (see NewScrypt), and Encrypt seals the
+// plaintext with AES-GCM under the derived key whichever KDF was used.
+package encrypter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pactus-project/pactus/wallet/encrypter/strength"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	methodNone            = "NONE"
+	methodArgon2idAESGCM  = "ARGON2ID-AES_256_GCM"
+	methodScryptAES128CTR = "SCRYPT-AES_128_CTR"
+	keyLen                = 32
+)
+
+// Encrypter holds the parameters needed to derive the encryption key from a
+// password. It is safe to marshal alongside the cipher text it protects.
+//
+// Two KDFs are supported: Argon2idAESGCM is used for the vault's own key
+// store, and ScryptAES128CTR matches the Web3 Secret Storage (keystore v3)
+// definition so BLS keys can be derived with the same code path whether
+// they're headed for the vault or for a keystore-v3 JSON file.
+type Encrypter struct {
+	Method string `json:"method"`
+	Params params `json:"params,omitempty"`
+}
+
+type params struct {
+	Iterations  uint32 `json:"iterations,omitempty"`
+	Memory      uint32 `json:"memory,omitempty"`
+	Parallelism uint8  `json:"parallelism,omitempty"`
+	N           int    `json:"n,omitempty"`
+	R           int    `json:"r,omitempty"`
+	P           int    `json:"p,omitempty"`
+	DKLen       int    `json:"dklen,omitempty"`
+	Salt        string `json:"salt,omitempty"`
+}
+
+// Keystore-v3 scrypt defaults, as specified by the Web3 Secret Storage
+// definition.
+const (
+	KeystoreV3ScryptN     = 262144
+	KeystoreV3ScryptR     = 8
+	KeystoreV3ScryptP     = 1
+	KeystoreV3ScryptDKLen = 32
+)
+
+// NewScrypt creates an Encrypter using the ScryptAES128CTR KDF with the
+// keystore-v3 default cost parameters and the given salt. Unlike New,
+// it does not apply a minimum password score: keystore-v3 files are an
+// interop format and their password policy is the peer tool's concern.
+func NewScrypt(salt []byte) Encrypter {
+	return NewScryptWithParams(salt, KeystoreV3ScryptN, KeystoreV3ScryptR, KeystoreV3ScryptP, KeystoreV3ScryptDKLen)
+}
+
+// NewScryptWithParams is like NewScrypt but with caller-supplied cost
+// parameters, for interoperating with a keystore-v3 file that was written
+// with non-default scrypt costs.
+func NewScryptWithParams(salt []byte, n, r, p, dklen int) Encrypter {
+	return Encrypter{
+		Method: methodScryptAES128CTR,
+		Params: params{N: n, R: r, P: p, DKLen: dklen, Salt: hex.EncodeToString(salt)},
+	}
+}
+
+// DeriveKey runs this Encrypter's KDF over password, returning the raw
+// derived key. Callers that need to build a non-default envelope around the
+// key (such as the keystore-v3 cipher+MAC layout) can use this directly
+// instead of Encrypt/Decrypt.
+func (e *Encrypter) DeriveKey(password string) ([]byte, error) {
+	salt, err := hex.DecodeString(e.Params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+
+	switch e.Method {
+	case methodArgon2idAESGCM:
+		return argon2.IDKey([]byte(password), salt,
+			e.Params.Iterations, e.Params.Memory, e.Params.Parallelism, keyLen), nil
+	case methodScryptAES128CTR:
+		return scrypt.Key([]byte(password), salt, e.Params.N, e.Params.R, e.Params.P, e.Params.DKLen)
+	default:
+		return nil, fmt.Errorf("%s: no KDF for method %q", "encrypter", e.Method)
+	}
+}
+
+// NoEncrypter returns an Encrypter that performs no encryption at all.
+func NoEncrypter() Encrypter {
+	return Encrypter{Method: methodNone}
+}
+
+// New creates a new Encrypter for password. An empty password disables
+// encryption. A non-empty password is scored with the strength package and
+// rejected with an ErrWeakPassword if it doesn't reach OptionMinPasswordScore.
+func New(password string, opts ...Option) (Encrypter, error) {
+	if password == "" {
+		return NoEncrypter(), nil
+	}
+
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	result := strength.Estimate(password)
+	if int(result.Score) < o.minScore {
+		return Encrypter{}, ErrWeakPassword{
+			Score:    int(result.Score),
+			Guesses:  result.Guesses,
+			Feedback: result.Feedback,
+		}
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return Encrypter{}, err
+	}
+
+	return Encrypter{
+		Method: methodArgon2idAESGCM,
+		Params: params{
+			Iterations:  uint32(o.iteration),
+			Memory:      uint32(o.memory),
+			Parallelism: uint8(o.parallelism),
+			Salt:        hex.EncodeToString(salt),
+		},
+	}, nil
+}
+
+// IsEncrypted reports whether e actually protects its message with a
+// password, as opposed to being a no-op NoEncrypter.
+func (e *Encrypter) IsEncrypted() bool {
+	return e.Method != "" && e.Method != methodNone
+}
+
+// Encrypt seals message with password, returning a hex-encoded nonce and
+// cipher text. Encrypt is a no-op, returning message unchanged, when e is
+// not encrypted.
+func (e *Encrypter) Encrypt(message, password string) (string, error) {
+	if !e.IsEncrypted() {
+		return message, nil
+	}
+
+	gcm, err := e.cipher(password)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(message), nil)
+	return hex.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a cipher text produced by Encrypt using password. It
+// returns ErrInvalidPassword if password is empty or doesn't match.
+func (e *Encrypter) Decrypt(cipherText, password string) (string, error) {
+	if !e.IsEncrypted() {
+		return cipherText, nil
+	}
+
+	if password == "" {
+		return "", ErrInvalidPassword
+	}
+
+	gcm, err := e.cipher(password)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := hex.DecodeString(cipherText)
+	if err != nil {
+		return "", ErrInvalidPassword
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", ErrInvalidPassword
+	}
+
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrInvalidPassword
+	}
+
+	return string(plain), nil
+}
+
+func (e *Encrypter) cipher(password string) (cipher.AEAD, error) {
+	key, err := e.DeriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
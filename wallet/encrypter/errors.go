@@ -0,0 +1,23 @@
+package encrypter
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPassword is returned when the supplied password fails to
+// decrypt the key store, or when a password is required but empty.
+var ErrInvalidPassword = errors.New("invalid password")
+
+// ErrWeakPassword is returned by New when a candidate password scores below
+// the configured OptionMinPasswordScore. It carries the estimator's output
+// so callers can surface actionable feedback to the user.
+type ErrWeakPassword struct {
+	Score    int
+	Guesses  float64
+	Feedback []string
+}
+
+func (e ErrWeakPassword) Error() string {
+	return fmt.Sprintf("password is too weak: score %d (estimated %.0f guesses)", e.Score, e.Guesses)
+}
@@ -0,0 +1,50 @@
+package encrypter
+
+// Option configures the key-derivation parameters and password policy used
+// by New.
+type Option func(*options)
+
+type options struct {
+	iteration   int
+	memory      int
+	parallelism int
+	minScore    int
+}
+
+func defaultOptions() *options {
+	return &options{
+		iteration:   3,
+		memory:      64 * 1024,
+		parallelism: 4,
+		minScore:    2,
+	}
+}
+
+// OptionIteration sets the Argon2id number of iterations (time cost).
+func OptionIteration(iteration int) Option {
+	return func(o *options) {
+		o.iteration = iteration
+	}
+}
+
+// OptionMemory sets the Argon2id memory cost, in KiB.
+func OptionMemory(memory int) Option {
+	return func(o *options) {
+		o.memory = memory
+	}
+}
+
+// OptionParallelism sets the Argon2id degree of parallelism.
+func OptionParallelism(parallelism int) Option {
+	return func(o *options) {
+		o.parallelism = parallelism
+	}
+}
+
+// OptionMinPasswordScore sets the minimum zxcvbn-style strength score
+// (0-4) a new password must reach before New accepts it. It defaults to 2.
+func OptionMinPasswordScore(score int) Option {
+	return func(o *options) {
+		o.minScore = score
+	}
+}
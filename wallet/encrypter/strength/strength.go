@@ -0,0 +1,216 @@
+// Package strength implements a small, dependency-light password strength
+// estimator in the spirit of zxcvbn. It runs a set of independent matchers
+// (dictionary, repeat/sequence, date, and a brute-force entropy fallback),
+// each of which rates the password as a whole, and reports the lowest guess
+// count any of them gives. The password is not split into parts that are
+// scored separately and combined.
+package strength
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+)
+
+// Score is a 0-4 strength rating, following zxcvbn's convention:
+// 0 - too guessable, 1 - very guessable, 2 - somewhat guessable,
+// 3 - safely unguessable, 4 - very unguessable.
+type Score int
+
+const (
+	ScoreTooGuessable Score = iota
+	ScoreVeryGuessable
+	ScoreSomewhatGuessable
+	ScoreSafelyUnguessable
+	ScoreVeryUnguessable
+)
+
+// scoreThresholds holds the minimum guesses required to reach each score,
+// expressed as log10(guesses): 0:<1e3, 1:<1e6, 2:<1e8, 3:<1e10, 4:>=1e10.
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+// Result is the outcome of estimating a password's strength.
+type Result struct {
+	Score    Score
+	Guesses  float64
+	Feedback []string
+}
+
+var (
+	dateSeparator = regexp.MustCompile(`^\d{1,4}[-/.]\d{1,2}[-/.]\d{1,4}$`)
+	dateCompact   = regexp.MustCompile(`^(19|20)\d{2}\d{2}\d{2}$|^\d{2}\d{2}(19|20)\d{2}$`)
+
+	keyboardWalks = []string{
+		"qwerty", "qwertyuiop", "asdf", "asdfgh", "asdfghjkl",
+		"zxcv", "zxcvbn", "zxcvbnm", "1qaz2wsx", "qazwsx",
+	}
+
+	commonPasswords = []string{
+		"password", "123456", "12345678", "qwerty", "abc123",
+		"letmein", "monkey", "dragon", "iloveyou", "admin",
+		"welcome", "login", "passw0rd", "trustno1", "sunshine",
+	}
+)
+
+// Estimate scores password, penalizing any userInputs (labels, addresses,
+// mnemonic words, ...) that appear verbatim so they can't be reused as a
+// password.
+func Estimate(password string, userInputs ...string) Result {
+	lower := strings.ToLower(password)
+
+	guesses := bruteForceGuesses(password)
+	feedback := make([]string, 0, 1)
+
+	if g, ok := dictionaryGuesses(lower, userInputs); ok && g < guesses {
+		guesses = g
+		feedback = append(feedback, "this is similar to a commonly used password")
+	}
+
+	if g, ok := repeatOrSequenceGuesses(lower); ok && g < guesses {
+		guesses = g
+		feedback = append(feedback, "avoid repeated or sequential characters")
+	}
+
+	if g, ok := dateGuesses(password); ok && g < guesses {
+		guesses = g
+		feedback = append(feedback, "avoid dates, they are easy to guess")
+	}
+
+	return Result{
+		Score:    scoreFromGuesses(guesses),
+		Guesses:  guesses,
+		Feedback: feedback,
+	}
+}
+
+func scoreFromGuesses(guesses float64) Score {
+	if guesses < 1 {
+		guesses = 1
+	}
+	log := math.Log10(guesses)
+	score := ScoreVeryUnguessable
+	for s, threshold := range scoreThresholds {
+		if log < threshold {
+			score = Score(s)
+			break
+		}
+	}
+	return score
+}
+
+// dictionaryGuesses matches the password (or any supplied user input) against
+// common passwords, keyboard walks, and BIP-39 mnemonic words. The rank in
+// the list is used as the guess count, the classic dictionary-attack model.
+func dictionaryGuesses(lower string, userInputs []string) (float64, bool) {
+	for rank, word := range commonPasswords {
+		if lower == word {
+			return float64(rank + 1), true
+		}
+	}
+
+	for rank, word := range keyboardWalks {
+		if lower == word {
+			return float64(rank + 1), true
+		}
+	}
+
+	for rank, word := range wordlists.English {
+		if lower == strings.ToLower(word) {
+			return float64(rank + 1), true
+		}
+	}
+
+	for _, input := range userInputs {
+		if input != "" && lower == strings.ToLower(input) {
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// repeatOrSequenceGuesses catches low-complexity patterns such as "aaaa",
+// "abcd" or "1234": a run of at least four characters anywhere in the
+// password that are either all the same, or where each is one code point
+// above or below the previous one. Once such a run is found, the whole
+// password is rated at twice its length in guesses.
+func repeatOrSequenceGuesses(lower string) (float64, bool) {
+	if len(lower) < 4 {
+		return 0, false
+	}
+
+	repeats := 1
+	sequential := 1
+	for i := 1; i < len(lower); i++ {
+		delta := int(lower[i]) - int(lower[i-1])
+		if delta == 0 {
+			repeats++
+		} else {
+			repeats = 1
+		}
+		if delta == 1 || delta == -1 {
+			sequential++
+		} else {
+			sequential = 1
+		}
+		if repeats >= 4 || sequential >= 4 {
+			// Sequences/repeats are trivial to guess: a handful of base
+			// patterns times the length of the password.
+			return float64(len(lower)) * 2, true
+		}
+	}
+
+	return 0, false
+}
+
+// dateGuesses catches dates such as "01/02/2006" or "20060102": an attacker
+// only needs to try the ~36,500 days in a century.
+func dateGuesses(password string) (float64, bool) {
+	if dateSeparator.MatchString(password) || dateCompact.MatchString(password) {
+		return 365 * 100, true
+	}
+	return 0, false
+}
+
+// bruteForceGuesses is the fallback matcher: Shannon-entropy style guess
+// count over the character classes actually used in the password, assuming
+// an attacker must try on average half of the keyspace.
+func bruteForceGuesses(password string) float64 {
+	pool := charPoolSize(password)
+	if pool == 0 {
+		return 1
+	}
+	return math.Pow(float64(pool), float64(len(password))) / 2
+}
+
+// charPoolSize maps the character classes present in password to the
+// escalating pool sizes used by the brute-force estimate: 26 for lowercase
+// only, 52 once uppercase joins in, 62 once digits join in, 94 once symbols
+// join in.
+func charPoolSize(password string) int {
+	var hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case hasSymbol:
+		return 94
+	case hasDigit:
+		return 62
+	case hasUpper:
+		return 52
+	default:
+		return 26
+	}
+}
@@ -0,0 +1,32 @@
+package strength_test
+
+import (
+	"testing"
+
+	"github.com/pactus-project/pactus/wallet/encrypter/strength"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateWeakPasswords(t *testing.T) {
+	weak := []string{
+		"password",
+		"12345678",
+		"abandon", // a BIP-39 wordlist entry
+	}
+
+	for _, pwd := range weak {
+		result := strength.Estimate(pwd)
+		assert.Lessf(t, int(result.Score), 2, "expected %q to be scored weak", pwd)
+		assert.NotEmpty(t, result.Feedback)
+	}
+}
+
+func TestEstimateStrongPassword(t *testing.T) {
+	result := strength.Estimate("Gr3en!Forest_42x")
+	assert.GreaterOrEqual(t, int(result.Score), 2)
+}
+
+func TestEstimatePenalizesUserInputs(t *testing.T) {
+	result := strength.Estimate("my-wallet-label", "my-wallet-label")
+	assert.Less(t, int(result.Score), 2)
+}
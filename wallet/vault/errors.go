@@ -0,0 +1,30 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNeutered is returned by vault operations that require a private key
+// (signing, exporting the mnemonic, importing keys, changing the password)
+// when called on a neutered (watch-only) vault.
+var ErrNeutered = errors.New("vault is neutered")
+
+// ErrAddressExists is returned when importing a private key whose address
+// is already tracked by the vault.
+var ErrAddressExists = errors.New("address already exists")
+
+// ErrAddressNotFound is returned when an address is not tracked by the
+// vault. Use NewErrAddressNotFound to build one with the offending address.
+type ErrAddressNotFound struct {
+	Address string
+}
+
+func (e ErrAddressNotFound) Error() string {
+	return fmt.Sprintf("address not found: %s", e.Address)
+}
+
+// NewErrAddressNotFound creates an ErrAddressNotFound for addr.
+func NewErrAddressNotFound(addr string) error {
+	return ErrAddressNotFound{Address: addr}
+}
@@ -0,0 +1,218 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/pactus-project/pactus/wallet/encrypter"
+	"golang.org/x/crypto/sha3"
+)
+
+// keystoreV3 is the Web3 Secret Storage envelope (version 3), extended with
+// a `pactus` object so a BLS key round-trips with its curve, address and HD
+// path intact.
+type keystoreV3 struct {
+	Version int              `json:"version"`
+	Address string           `json:"address,omitempty"`
+	Crypto  keystoreV3Crypto `json:"crypto"`
+	Pactus  keystoreV3Pactus `json:"pactus"`
+}
+
+type keystoreV3Crypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams keystoreV3CipherParams `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    keystoreV3KDFParams    `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type keystoreV3CipherParams struct {
+	IV string `json:"iv"`
+}
+
+type keystoreV3KDFParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+type keystoreV3Pactus struct {
+	Curve   string `json:"curve"`
+	Address string `json:"address,omitempty"`
+	HDPath  string `json:"hdpath,omitempty"`
+}
+
+const keystoreV3CipherName = "aes-128-ctr"
+
+// ExportKeystoreV3 encrypts the private key for addr into a Web3 Secret
+// Storage (keystore v3) JSON blob protected by newPassword, so it can be
+// carried into Ethereum-style tooling. password unlocks the vault itself.
+func (v *Vault) ExportKeystoreV3(password, addr, newPassword string) ([]byte, error) {
+	keys, err := v.PrivateKeys(password, []string{addr})
+	if err != nil {
+		return nil, err
+	}
+
+	prv, ok := keys[0].(*bls.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("address %s is not a BLS key", addr)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	enc := encrypter.NewScrypt(salt)
+	dk, err := enc.DeriveKey(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(dk[:16])
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText := make([]byte, len(prv.Bytes()))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, prv.Bytes())
+
+	mac := keystoreV3MAC(dk, cipherText)
+
+	info := v.AddressInfo(addr)
+	hdPath := ""
+	if info != nil {
+		hdPath = info.Path
+	}
+
+	ks := keystoreV3{
+		Version: 3,
+		Address: addr,
+		Crypto: keystoreV3Crypto{
+			Cipher:       keystoreV3CipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreV3CipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          "scrypt",
+			KDFParams: keystoreV3KDFParams{
+				N:     encrypter.KeystoreV3ScryptN,
+				R:     encrypter.KeystoreV3ScryptR,
+				P:     encrypter.KeystoreV3ScryptP,
+				DKLen: encrypter.KeystoreV3ScryptDKLen,
+				Salt:  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		Pactus: keystoreV3Pactus{
+			Curve:   "bls12-381",
+			Address: addr,
+			HDPath:  hdPath,
+		},
+	}
+
+	return json.Marshal(ks)
+}
+
+// ImportKeystoreV3 decrypts a keystore-v3 JSON blob with blobPassword and
+// imports the recovered BLS key into the vault, unlocked with password.
+func (v *Vault) ImportKeystoreV3(password string, jsonBlob []byte, blobPassword string) error {
+	ks := new(keystoreV3)
+	if err := json.Unmarshal(jsonBlob, ks); err != nil {
+		return fmt.Errorf("invalid keystore-v3 file: %w", err)
+	}
+
+	if ks.Version != 3 {
+		return fmt.Errorf("unsupported keystore version: %d", ks.Version)
+	}
+
+	if ks.Crypto.KDF != "scrypt" {
+		return fmt.Errorf("unsupported keystore kdf: %s", ks.Crypto.KDF)
+	}
+
+	if ks.Crypto.Cipher != keystoreV3CipherName {
+		return fmt.Errorf("unsupported keystore cipher: %s", ks.Crypto.Cipher)
+	}
+
+	salt, err := hex.DecodeString(ks.Crypto.KDFParams.Salt)
+	if err != nil {
+		return fmt.Errorf("invalid keystore salt: %w", err)
+	}
+
+	// scrypt.Key returns a key of exactly dklen bytes with no error, so a
+	// short dklen from the file must be rejected before it's used to slice
+	// the derived key below.
+	if ks.Crypto.KDFParams.DKLen < 32 {
+		return fmt.Errorf("invalid keystore kdfparams: dklen must be at least 32, got %d",
+			ks.Crypto.KDFParams.DKLen)
+	}
+
+	enc := encrypter.NewScryptWithParams(salt,
+		ks.Crypto.KDFParams.N, ks.Crypto.KDFParams.R, ks.Crypto.KDFParams.P, ks.Crypto.KDFParams.DKLen)
+
+	dk, err := enc.DeriveKey(blobPassword)
+	if err != nil {
+		return err
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+
+	wantMAC, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return fmt.Errorf("invalid keystore mac: %w", err)
+	}
+
+	if !bytes.Equal(keystoreV3MAC(dk, cipherText), wantMAC) {
+		return encrypter.ErrInvalidPassword
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return fmt.Errorf("invalid keystore iv: %w", err)
+	}
+
+	// cipher.NewCTR panics if the IV length doesn't match the block size,
+	// so a malformed iv from the file must be rejected before it's used.
+	if len(iv) != aes.BlockSize {
+		return fmt.Errorf("invalid keystore iv: must be %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+
+	block, err := aes.NewCipher(dk[:16])
+	if err != nil {
+		return err
+	}
+
+	prvBytes := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(prvBytes, cipherText)
+
+	prv, err := bls.PrivateKeyFromBytes(prvBytes)
+	if err != nil {
+		return fmt.Errorf("invalid keystore private key: %w", err)
+	}
+
+	return v.ImportPrivateKey(password, prv)
+}
+
+// keystoreV3MAC is the Web3 Secret Storage MAC: keccak256(dk[16:32] || cipherText).
+func keystoreV3MAC(dk, cipherText []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(dk[16:32])
+	h.Write(cipherText)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,43 @@
+package vault
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pactus-project/pactus/wallet/encrypter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatePasswordRejectsWeakPassword(t *testing.T) {
+	td := setup(t)
+
+	firstWord := td.mnemonic
+	if i := strings.IndexByte(td.mnemonic, ' '); i >= 0 {
+		firstWord = td.mnemonic[:i]
+	}
+
+	weak := []string{
+		"password",
+		"12345678",
+		firstWord,
+	}
+
+	for _, pwd := range weak {
+		err := td.vault.UpdatePassword(tPassword, pwd)
+		var weakErr encrypter.ErrWeakPassword
+		assert.ErrorAs(t, err, &weakErr)
+		assert.Less(t, weakErr.Score, 2)
+	}
+}
+
+func TestUpdatePasswordAcceptsStrongPassword(t *testing.T) {
+	td := setup(t)
+
+	opts := []encrypter.Option{
+		encrypter.OptionIteration(1),
+		encrypter.OptionMemory(1),
+		encrypter.OptionParallelism(1),
+	}
+
+	assert.NoError(t, td.vault.UpdatePassword(tPassword, "Gr3en!Forest_42x", opts...))
+}
@@ -0,0 +1,40 @@
+package vault
+
+// Role identifies the branch of a BLS account used to derive an address:
+// validator keys and account (spending) keys live under different indices
+// so that validator and account addresses never collide.
+type Role uint32
+
+const (
+	purposeBLS = uint32(12381)
+
+	RoleValidator Role = 1
+	RoleAccount   Role = 2
+)
+
+// AddressInfo describes a single tracked address: its public key, the
+// derivation path that produced it (empty for imported keys; relative to
+// the owning xpub, e.g. "M/3/0" for watch-only addresses, where each
+// segment is hardened), and a user-facing label.
+type AddressInfo struct {
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+	Path      string `json:"path"`
+	Label     string `json:"label"`
+}
+
+// BLSAccount is the BIP44-style key tree rooted at m/12381'/coinType'. Its
+// XPubAccount is the neutered (public-only) extended key at that level, so a
+// Vault.Neuter() copy can keep deriving addresses without the private keys.
+type BLSAccount struct {
+	XPubAccount        string                  `json:"x_pub_account"`
+	AddressInfo        map[string]*AddressInfo `json:"address_info"`
+	NextAccountIndex   int                     `json:"next_account_index"`
+	NextValidatorIndex int                     `json:"next_validator_index"`
+}
+
+// Purposes groups the key trees a Vault manages, keyed by BIP44 purpose.
+type Purposes struct {
+	PurposeBLS *BLSAccount      `json:"purpose_bls,omitempty"`
+	WatchOnly  []*WatchOnlyXPub `json:"watch_only,omitempty"`
+}
@@ -0,0 +1,503 @@
+// Package vault implements the encrypted key storage backing a Pactus
+// wallet: an HD key tree for BLS account and validator addresses, plus a
+// password-protected store for the mnemonic and any imported keys.
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/crypto/bls"
+	"github.com/pactus-project/pactus/crypto/bls/hdkeychain"
+	"github.com/pactus-project/pactus/wallet/encrypter"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Vault is the encrypted container for a wallet's keys. The zero value is
+// not usable; create one with CreateVaultFromMnemonic.
+type Vault struct {
+	Encrypter encrypter.Encrypter `json:"crypto"`
+	KeyStore  string              `json:"key_store"`
+	Purposes  Purposes            `json:"purposes"`
+
+	mnemonic     string
+	importedKeys map[string]crypto.PrivateKey
+}
+
+// keyStoreData is the plaintext payload sealed inside Vault.KeyStore.
+type keyStoreData struct {
+	Mnemonic     string            `json:"mnemonic"`
+	ImportedKeys map[string]string `json:"imported_keys"` // address -> hex private key
+}
+
+// GenerateMnemonic creates a new BIP-39 mnemonic of the given entropy bit
+// size (128, 160, 192, 224 or 256).
+func GenerateMnemonic(bitSize int) (string, error) {
+	entropy, err := bip39.NewEntropy(bitSize)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// CreateVaultFromMnemonic recovers (or creates) a Vault from mnemonic,
+// deriving the BLS account key tree under the given coinType.
+func CreateVaultFromMnemonic(mnemonic string, coinType uint32) (*Vault, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+
+	seed := bip39.NewSeed(mnemonic, "")
+	accountKey, err := deriveBLSAccountKey(seed, coinType)
+	if err != nil {
+		return nil, err
+	}
+
+	neutered, err := accountKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Vault{
+		Encrypter: encrypter.NoEncrypter(),
+		Purposes: Purposes{
+			PurposeBLS: &BLSAccount{
+				XPubAccount: neutered.String(),
+				AddressInfo: make(map[string]*AddressInfo),
+			},
+		},
+		mnemonic:     mnemonic,
+		importedKeys: make(map[string]crypto.PrivateKey),
+	}
+
+	if err := v.saveKeyStore(""); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func deriveBLSAccountKey(seed []byte, coinType uint32) (*hdkeychain.ExtendedKey, error) {
+	master, err := hdkeychain.NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	purposeKey, err := master.Derive(hdkeychain.HardenedKeyStart + purposeBLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return purposeKey.Derive(hdkeychain.HardenedKeyStart + coinType)
+}
+
+// IsNeutered reports whether v holds only public keys, i.e. it has no
+// mnemonic and no imported private keys.
+func (v *Vault) IsNeutered() bool {
+	return v.mnemonic == "" && len(v.importedKeys) == 0
+}
+
+// IsEncrypted reports whether the vault's key store is password protected.
+func (v *Vault) IsEncrypted() bool {
+	return v.Encrypter.IsEncrypted()
+}
+
+// AddressCount returns the number of addresses tracked by the vault,
+// including watch-only addresses derived from an imported xpub.
+func (v *Vault) AddressCount() int {
+	return len(v.allAddressInfo())
+}
+
+// AddressInfos returns every address tracked by the vault, including
+// watch-only addresses derived from an imported xpub.
+func (v *Vault) AddressInfos() []*AddressInfo {
+	all := v.allAddressInfo()
+	infos := make([]*AddressInfo, 0, len(all))
+	for _, info := range all {
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// AddressInfo returns the info tracked for addr, or nil if it is unknown.
+func (v *Vault) AddressInfo(addr string) *AddressInfo {
+	return v.allAddressInfo()[addr]
+}
+
+// allAddressInfo merges the vault's own BLS addresses with every watch-only
+// xpub's addresses.
+func (v *Vault) allAddressInfo() map[string]*AddressInfo {
+	all := make(map[string]*AddressInfo, len(v.Purposes.PurposeBLS.AddressInfo))
+	for addr, info := range v.Purposes.PurposeBLS.AddressInfo {
+		all[addr] = info
+	}
+
+	for _, w := range v.Purposes.WatchOnly {
+		for addr, info := range w.AddressInfo {
+			all[addr] = info
+		}
+	}
+
+	return all
+}
+
+// Contains reports whether addr is tracked by the vault.
+func (v *Vault) Contains(addr string) bool {
+	return v.AddressInfo(addr) != nil
+}
+
+// Label returns the label set for addr, or "" if it has none.
+func (v *Vault) Label(addr string) string {
+	info := v.AddressInfo(addr)
+	if info == nil {
+		return ""
+	}
+
+	return info.Label
+}
+
+// SetLabel updates the label for addr.
+func (v *Vault) SetLabel(addr, label string) error {
+	info := v.AddressInfo(addr)
+	if info == nil {
+		return NewErrAddressNotFound(addr)
+	}
+
+	info.Label = label
+
+	return nil
+}
+
+// NewBLSAccountAddress derives the next BLS account (spending) address and
+// labels it.
+func (v *Vault) NewBLSAccountAddress(label string) (string, error) {
+	return v.newBLSAddress(RoleAccount, label)
+}
+
+// NewValidatorAddress derives the next BLS validator address and labels it.
+func (v *Vault) NewValidatorAddress(label string) (string, error) {
+	return v.newBLSAddress(RoleValidator, label)
+}
+
+func (v *Vault) newBLSAddress(role Role, label string) (string, error) {
+	account := v.Purposes.PurposeBLS
+
+	index := account.NextAccountIndex
+	if role == RoleValidator {
+		index = account.NextValidatorIndex
+	}
+
+	accountKey, err := hdkeychain.NewKeyFromString(account.XPubAccount)
+	if err != nil {
+		return "", err
+	}
+
+	roleKey, err := accountKey.Derive(hdkeychain.HardenedKeyStart + uint32(role))
+	if err != nil {
+		return "", err
+	}
+
+	addrKey, err := roleKey.Derive(hdkeychain.HardenedKeyStart + uint32(index))
+	if err != nil {
+		return "", err
+	}
+
+	pub, err := addrKey.BLSPublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	var addr crypto.Address
+	if role == RoleValidator {
+		addr = pub.ValidatorAddress()
+	} else {
+		addr = pub.AccountAddress()
+	}
+
+	path := fmt.Sprintf("m/%d'/%d'/%d'/%d", purposeBLS, accountKey.CoinType(), role, index)
+	account.AddressInfo[addr.String()] = &AddressInfo{
+		Address:   addr.String(),
+		PublicKey: pub.String(),
+		Path:      path,
+		Label:     label,
+	}
+
+	if role == RoleValidator {
+		account.NextValidatorIndex++
+	} else {
+		account.NextAccountIndex++
+	}
+
+	return addr.String(), nil
+}
+
+// ImportPrivateKey adds prv to the vault under its native account and
+// validator addresses. It requires password to unlock the key store unless
+// the vault is unencrypted.
+func (v *Vault) ImportPrivateKey(password string, prv crypto.PrivateKey) error {
+	if v.IsNeutered() {
+		return ErrNeutered
+	}
+
+	blsPrv, ok := prv.(*bls.PrivateKey)
+	if !ok {
+		return fmt.Errorf("only BLS keys can be imported")
+	}
+
+	pub := blsPrv.PublicKeyNative()
+	accAddr := pub.AccountAddress().String()
+	valAddr := pub.ValidatorAddress().String()
+
+	if v.Contains(accAddr) || v.Contains(valAddr) {
+		return ErrAddressExists
+	}
+
+	if err := v.unlock(password); err != nil {
+		return err
+	}
+
+	v.importedKeys[accAddr] = prv
+	v.importedKeys[valAddr] = prv
+	v.Purposes.PurposeBLS.AddressInfo[accAddr] = &AddressInfo{
+		Address:   accAddr,
+		PublicKey: pub.String(),
+	}
+	v.Purposes.PurposeBLS.AddressInfo[valAddr] = &AddressInfo{
+		Address:   valAddr,
+		PublicKey: pub.String(),
+	}
+
+	// password isn't changing here, so re-seal under the vault's existing
+	// Encrypter instead of saveKeyStore, which would rebuild it from
+	// defaultOptions() and discard any custom KDF cost params, and re-run
+	// the password-strength check against an already-accepted password.
+	return v.resealKeyStore(password)
+}
+
+// PrivateKeys returns the private keys for addrs, decrypting the key store
+// with password.
+func (v *Vault) PrivateKeys(password string, addrs []string) ([]crypto.PrivateKey, error) {
+	if v.IsNeutered() {
+		return nil, ErrNeutered
+	}
+
+	if err := v.unlock(password); err != nil {
+		return nil, err
+	}
+
+	keys := make([]crypto.PrivateKey, 0, len(addrs))
+	for _, addr := range addrs {
+		info := v.AddressInfo(addr)
+		if info == nil {
+			return nil, NewErrAddressNotFound(addr)
+		}
+
+		if v.isWatchOnlyAddress(addr) {
+			return nil, ErrNeutered
+		}
+
+		if imported, ok := v.importedKeys[addr]; ok {
+			keys = append(keys, imported)
+			continue
+		}
+
+		prv, err := v.derivePrivateKeyForPath(info.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, prv)
+	}
+
+	return keys, nil
+}
+
+func (v *Vault) derivePrivateKeyForPath(path string) (crypto.PrivateKey, error) {
+	var purpose, coinType, role, index uint32
+	if _, err := fmt.Sscanf(path, "m/%d'/%d'/%d'/%d", &purpose, &coinType, &role, &index); err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %w", path, err)
+	}
+
+	seed := bip39.NewSeed(v.mnemonic, "")
+	master, err := hdkeychain.NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key := master
+	for _, segment := range []uint32{purpose, coinType, role, index} {
+		key, err = key.Derive(hdkeychain.HardenedKeyStart + segment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key.BLSPrivateKey()
+}
+
+// Mnemonic decrypts and returns the recovery phrase backing the vault.
+func (v *Vault) Mnemonic(password string) (string, error) {
+	if v.IsNeutered() {
+		return "", ErrNeutered
+	}
+
+	if err := v.unlock(password); err != nil {
+		return "", err
+	}
+
+	return v.mnemonic, nil
+}
+
+// UpdatePassword re-encrypts the key store under newPassword, which must be
+// unlocked first with oldPassword. opts configure the Argon2id parameters
+// and the minimum accepted password strength (see encrypter.Option); a weak
+// newPassword is rejected with an encrypter.ErrWeakPassword.
+func (v *Vault) UpdatePassword(oldPassword, newPassword string, opts ...encrypter.Option) error {
+	if v.IsNeutered() {
+		return ErrNeutered
+	}
+
+	if err := v.unlock(oldPassword); err != nil {
+		return err
+	}
+
+	return v.saveKeyStore(newPassword, opts...)
+}
+
+// unlock decrypts the key store with password and populates the vault's
+// in-memory mnemonic and imported keys.
+func (v *Vault) unlock(password string) error {
+	if v.Encrypter.IsEncrypted() && password == "" {
+		return encrypter.ErrInvalidPassword
+	}
+
+	plain, err := v.Encrypter.Decrypt(v.KeyStore, password)
+	if err != nil {
+		return err
+	}
+
+	data := new(keyStoreData)
+	if err := json.Unmarshal([]byte(plain), data); err != nil {
+		return encrypter.ErrInvalidPassword
+	}
+
+	v.mnemonic = data.Mnemonic
+	v.importedKeys = make(map[string]crypto.PrivateKey, len(data.ImportedKeys))
+	for addr, hexKey := range data.ImportedKeys {
+		prv, err := bls.PrivateKeyFromString(hexKey)
+		if err != nil {
+			return err
+		}
+
+		v.importedKeys[addr] = prv
+	}
+
+	return nil
+}
+
+// saveKeyStore seals the in-memory mnemonic and imported keys under
+// password, replacing v.KeyStore and v.Encrypter with a freshly built
+// Encrypter. Use this when password itself may be changing (creation,
+// UpdatePassword); use resealKeyStore when it isn't.
+func (v *Vault) saveKeyStore(password string, opts ...encrypter.Option) error {
+	enc, err := encrypter.New(password, opts...)
+	if err != nil {
+		return err
+	}
+
+	return v.sealKeyStore(enc, password)
+}
+
+// resealKeyStore re-seals the in-memory mnemonic and imported keys under the
+// vault's current Encrypter and password, without rebuilding its KDF
+// parameters or re-checking password strength. Use this after a change that
+// doesn't touch the password, such as ImportPrivateKey.
+func (v *Vault) resealKeyStore(password string) error {
+	return v.sealKeyStore(v.Encrypter, password)
+}
+
+// sealKeyStore marshals the in-memory mnemonic and imported keys and seals
+// them with enc, replacing v.KeyStore and v.Encrypter.
+func (v *Vault) sealKeyStore(enc encrypter.Encrypter, password string) error {
+	data := &keyStoreData{
+		Mnemonic:     v.mnemonic,
+		ImportedKeys: make(map[string]string, len(v.importedKeys)),
+	}
+	for addr, prv := range v.importedKeys {
+		data.ImportedKeys[addr] = prv.String()
+	}
+
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := enc.Encrypt(string(plain), password)
+	if err != nil {
+		return err
+	}
+
+	v.Encrypter = enc
+	v.KeyStore = sealed
+
+	return nil
+}
+
+// Neuter returns a watch-only copy of v: it keeps the HD-derived addresses
+// and their public keys, but drops the mnemonic and any imported keys since
+// those can't be reconstructed from the public key tree alone.
+func (v *Vault) Neuter() *Vault {
+	addressInfo := make(map[string]*AddressInfo, len(v.Purposes.PurposeBLS.AddressInfo))
+	for addr, info := range v.Purposes.PurposeBLS.AddressInfo {
+		if info.Path == "" {
+			continue // imported key, not derivable from the public key tree
+		}
+
+		cp := *info
+		addressInfo[addr] = &cp
+	}
+
+	return &Vault{
+		Encrypter: encrypter.NoEncrypter(),
+		Purposes: Purposes{
+			PurposeBLS: &BLSAccount{
+				XPubAccount:        v.Purposes.PurposeBLS.XPubAccount,
+				AddressInfo:        addressInfo,
+				NextAccountIndex:   v.Purposes.PurposeBLS.NextAccountIndex,
+				NextValidatorIndex: v.Purposes.PurposeBLS.NextValidatorIndex,
+			},
+			WatchOnly: copyWatchOnly(v.Purposes.WatchOnly),
+		},
+		importedKeys: make(map[string]crypto.PrivateKey),
+	}
+}
+
+// copyWatchOnly deep-copies imported xpubs verbatim: they carry no private
+// key material, so neutering a vault must never drop or alter them.
+func copyWatchOnly(in []*WatchOnlyXPub) []*WatchOnlyXPub {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*WatchOnlyXPub, len(in))
+	for i, w := range in {
+		addressInfo := make(map[string]*AddressInfo, len(w.AddressInfo))
+		for addr, info := range w.AddressInfo {
+			cp := *info
+			addressInfo[addr] = &cp
+		}
+
+		out[i] = &WatchOnlyXPub{
+			Purpose:     w.Purpose,
+			XPub:        w.XPub,
+			Label:       w.Label,
+			AddressInfo: addressInfo,
+			NextIndex:   w.NextIndex,
+		}
+	}
+
+	return out
+}
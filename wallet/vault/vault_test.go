@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -211,6 +212,56 @@ func TestImportPrivateKey(t *testing.T) {
 		assert.True(t, td.vault.Contains(prv.PublicKeyNative().AccountAddress().String()))
 		assert.True(t, td.vault.Contains(prv.PublicKeyNative().ValidatorAddress().String()))
 	})
+
+	t.Run("Preserves the vault's existing KDF params", func(t *testing.T) {
+		opts := []encrypter.Option{
+			encrypter.OptionIteration(1), encrypter.OptionMemory(1), encrypter.OptionParallelism(1),
+		}
+		require.NoError(t, td.vault.UpdatePassword(tPassword, tPassword, opts...))
+		wantEncrypter := td.vault.Encrypter
+
+		_, prv := td.RandBLSKeyPair()
+		require.NoError(t, td.vault.ImportPrivateKey(tPassword, prv))
+
+		assert.Equal(t, wantEncrypter, td.vault.Encrypter)
+	})
+
+	t.Run("Keystore-v3 round trip", func(t *testing.T) {
+		addr := td.vault.AddressInfos()[0].Address
+
+		blob, err := td.vault.ExportKeystoreV3(tPassword, addr, "ks-password")
+		assert.NoError(t, err)
+
+		fresh, err := CreateVaultFromMnemonic(td.mnemonic, 21888)
+		assert.NoError(t, err)
+
+		err = fresh.ImportKeystoreV3("", blob, "wrong-password")
+		assert.ErrorIs(t, err, encrypter.ErrInvalidPassword)
+
+		err = fresh.ImportKeystoreV3("", blob, "ks-password")
+		assert.NoError(t, err)
+		assert.True(t, fresh.Contains(addr))
+	})
+
+	t.Run("Malformed IV", func(t *testing.T) {
+		addr := td.vault.AddressInfos()[0].Address
+
+		blob, err := td.vault.ExportKeystoreV3(tPassword, addr, "ks-password")
+		assert.NoError(t, err)
+
+		var ks map[string]any
+		assert.NoError(t, json.Unmarshal(blob, &ks))
+		crypto, _ := ks["crypto"].(map[string]any)
+		crypto["cipherparams"] = map[string]any{"iv": "ab"}
+		blob, err = json.Marshal(ks)
+		assert.NoError(t, err)
+
+		fresh, err := CreateVaultFromMnemonic(td.mnemonic, 21888)
+		assert.NoError(t, err)
+
+		err = fresh.ImportKeystoreV3("", blob, "ks-password")
+		assert.Error(t, err)
+	})
 }
 
 func TestGetMnemonic(t *testing.T) {
@@ -0,0 +1,131 @@
+package vault
+
+import (
+	"fmt"
+
+	"github.com/pactus-project/pactus/crypto"
+	"github.com/pactus-project/pactus/crypto/bls/hdkeychain"
+)
+
+// WatchOnlyXPub is a key tree derived from an extended public key this
+// vault never held the private half of: a hardware signer, a multisig
+// coordinator, or any other external source. It lives in its own Purposes
+// slot so it's never confused with the vault's own PurposeBLS tree.
+type WatchOnlyXPub struct {
+	Purpose     uint32                  `json:"purpose"`
+	XPub        string                  `json:"x_pub"`
+	Label       string                  `json:"label"`
+	AddressInfo map[string]*AddressInfo `json:"address_info"`
+	NextIndex   int                     `json:"next_index"`
+}
+
+// ErrXPubExists is returned by ImportXPub when xpub is already imported.
+var ErrXPubExists = fmt.Errorf("xpub already imported")
+
+// ErrXPubNotFound is returned by NewWatchOnlyAddress when xpub was never
+// imported with ImportXPub.
+var ErrXPubNotFound = fmt.Errorf("xpub not found")
+
+// ImportXPub adds xpub as a watch-only key tree under the given BIP44
+// purpose, distinct from the vault's own PurposeBLS. It never requires a
+// password and works on a neutered vault, since it adds no private key
+// material.
+func (v *Vault) ImportXPub(purpose uint32, xpub string, label string) error {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return err
+	}
+
+	if key.IsPrivate() {
+		return fmt.Errorf("expected an extended public key, not a private one")
+	}
+
+	for _, w := range v.Purposes.WatchOnly {
+		if w.XPub == xpub {
+			return ErrXPubExists
+		}
+	}
+
+	v.Purposes.WatchOnly = append(v.Purposes.WatchOnly, &WatchOnlyXPub{
+		Purpose:     purpose,
+		XPub:        xpub,
+		Label:       label,
+		AddressInfo: make(map[string]*AddressInfo),
+	})
+
+	return nil
+}
+
+// NewWatchOnlyAddress derives the next address under a previously imported
+// xpub and labels it. The address is a validator address if the xpub was
+// imported with RoleValidator as its purpose, and an account address
+// otherwise.
+func (v *Vault) NewWatchOnlyAddress(xpub, label string) (string, error) {
+	w := v.findWatchOnly(xpub)
+	if w == nil {
+		return "", ErrXPubNotFound
+	}
+
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return "", err
+	}
+
+	purposeKey, err := key.Derive(hdkeychain.HardenedKeyStart + w.Purpose)
+	if err != nil {
+		return "", err
+	}
+
+	addrKey, err := purposeKey.Derive(hdkeychain.HardenedKeyStart + uint32(w.NextIndex))
+	if err != nil {
+		return "", err
+	}
+
+	pub, err := addrKey.BLSPublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	var addr crypto.Address
+	if Role(w.Purpose) == RoleValidator {
+		addr = pub.ValidatorAddress()
+	} else {
+		addr = pub.AccountAddress()
+	}
+
+	// The path is relative to the imported xpub: we don't know, and don't
+	// need to know, the absolute path that produced it upstream.
+	path := fmt.Sprintf("M/%d/%d", w.Purpose, w.NextIndex)
+
+	w.AddressInfo[addr.String()] = &AddressInfo{
+		Address:   addr.String(),
+		PublicKey: pub.String(),
+		Path:      path,
+		Label:     label,
+	}
+	w.NextIndex++
+
+	return addr.String(), nil
+}
+
+func (v *Vault) findWatchOnly(xpub string) *WatchOnlyXPub {
+	for _, w := range v.Purposes.WatchOnly {
+		if w.XPub == xpub {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// isWatchOnlyAddress reports whether addr belongs to an imported xpub
+// rather than the vault's own BLS key tree.
+func (v *Vault) isWatchOnlyAddress(addr string) bool {
+	for _, w := range v.Purposes.WatchOnly {
+		if _, ok := w.AddressInfo[addr]; ok {
+			return true
+		}
+	}
+
+	return false
+}
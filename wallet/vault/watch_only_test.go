@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pactus-project/pactus/wallet/encrypter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// externalXPub returns the neutered account-level xpub of a second,
+// unrelated mnemonic, standing in for a hardware signer or coordinator
+// that was never held by this vault.
+func externalXPub(t *testing.T) string {
+	t.Helper()
+
+	mnemonic, err := GenerateMnemonic(128)
+	require.NoError(t, err)
+
+	external, err := CreateVaultFromMnemonic(mnemonic, 21888)
+	require.NoError(t, err)
+
+	return external.Purposes.PurposeBLS.XPubAccount
+}
+
+func TestImportXPub(t *testing.T) {
+	td := setup(t)
+	xpub := externalXPub(t)
+
+	t.Run("Invalid xpub", func(t *testing.T) {
+		assert.Error(t, td.vault.ImportXPub(uint32(RoleAccount), "not-an-xpub", ""))
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		assert.NoError(t, td.vault.ImportXPub(uint32(RoleAccount), xpub, "hardware-signer"))
+	})
+
+	t.Run("Reimporting the same xpub", func(t *testing.T) {
+		assert.ErrorIs(t, td.vault.ImportXPub(uint32(RoleAccount), xpub, ""), ErrXPubExists)
+	})
+}
+
+func TestNewWatchOnlyAddress(t *testing.T) {
+	td := setup(t)
+	xpub := externalXPub(t)
+
+	t.Run("Unknown xpub", func(t *testing.T) {
+		_, err := td.vault.NewWatchOnlyAddress(xpub, "")
+		assert.ErrorIs(t, err, ErrXPubNotFound)
+	})
+
+	require.NoError(t, td.vault.ImportXPub(uint32(RoleAccount), xpub, "hardware-signer"))
+
+	addr, err := td.vault.NewWatchOnlyAddress(xpub, "watch-1")
+	require.NoError(t, err)
+
+	t.Run("Shows up in AddressInfos with its real path", func(t *testing.T) {
+		assert.True(t, td.vault.Contains(addr))
+		info := td.vault.AddressInfo(addr)
+		assert.Equal(t, "watch-1", info.Label)
+		assert.NotEmpty(t, info.Path)
+	})
+
+	t.Run("PrivateKeys refuses even on a non-neutered vault", func(t *testing.T) {
+		_, err := td.vault.PrivateKeys(tPassword, []string{addr})
+		assert.ErrorIs(t, err, ErrNeutered)
+	})
+
+	t.Run("Matches the external vault's own address at the same role/index", func(t *testing.T) {
+		mnemonic, err := GenerateMnemonic(128)
+		require.NoError(t, err)
+
+		external, err := CreateVaultFromMnemonic(mnemonic, 21888)
+		require.NoError(t, err)
+
+		wantAddr, err := external.NewBLSAccountAddress("")
+		require.NoError(t, err)
+
+		watchOnly := setup(t)
+		require.NoError(t, watchOnly.vault.ImportXPub(uint32(RoleAccount), external.Purposes.PurposeBLS.XPubAccount, ""))
+
+		gotAddr, err := watchOnly.vault.NewWatchOnlyAddress(external.Purposes.PurposeBLS.XPubAccount, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, wantAddr, gotAddr)
+	})
+
+	t.Run("Validator-purpose xpub derives validator addresses", func(t *testing.T) {
+		mnemonic, err := GenerateMnemonic(128)
+		require.NoError(t, err)
+
+		external, err := CreateVaultFromMnemonic(mnemonic, 21888)
+		require.NoError(t, err)
+
+		wantAddr, err := external.NewValidatorAddress("")
+		require.NoError(t, err)
+
+		watchOnly := setup(t)
+		require.NoError(t,
+			watchOnly.vault.ImportXPub(uint32(RoleValidator), external.Purposes.PurposeBLS.XPubAccount, ""))
+
+		gotAddr, err := watchOnly.vault.NewWatchOnlyAddress(external.Purposes.PurposeBLS.XPubAccount, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, wantAddr, gotAddr)
+	})
+
+	t.Run("IsEncrypted/UpdatePassword are unaffected", func(t *testing.T) {
+		assert.True(t, td.vault.IsEncrypted())
+		opts := []encrypter.Option{
+			encrypter.OptionIteration(1), encrypter.OptionMemory(1), encrypter.OptionParallelism(1),
+		}
+		assert.NoError(t, td.vault.UpdatePassword(tPassword, "new-watch-pw", opts...))
+		assert.NoError(t, td.vault.UpdatePassword("new-watch-pw", tPassword, opts...))
+	})
+}
+
+func TestWatchOnlyPersistenceAndNeuter(t *testing.T) {
+	td := setup(t)
+	xpub := externalXPub(t)
+
+	require.NoError(t, td.vault.ImportXPub(uint32(RoleAccount), xpub, "hardware-signer"))
+	addr, err := td.vault.NewWatchOnlyAddress(xpub, "watch-1")
+	require.NoError(t, err)
+
+	t.Run("Round-trip persistence", func(t *testing.T) {
+		data, err := json.Marshal(td.vault)
+		require.NoError(t, err)
+
+		restored := new(Vault)
+		require.NoError(t, json.Unmarshal(data, restored))
+
+		assert.Equal(t, td.vault.Purposes.WatchOnly, restored.Purposes.WatchOnly)
+		assert.True(t, restored.Contains(addr))
+	})
+
+	t.Run("Neuter preserves imported xpubs verbatim", func(t *testing.T) {
+		neutered := td.vault.Neuter()
+		assert.Equal(t, td.vault.Purposes.WatchOnly, neutered.Purposes.WatchOnly)
+		assert.True(t, neutered.Contains(addr))
+	})
+}